@@ -0,0 +1,251 @@
+package main
+
+import (
+	"consul-telegram-bot/internal/buybot"
+	"consul-telegram-bot/internal/config"
+	"consul-telegram-bot/internal/logger"
+	"consul-telegram-bot/internal/model"
+	"consul-telegram-bot/internal/store"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const anchorSignature = "replay-anchor"
+
+type fixtures struct {
+	dir        string
+	signatures []buybot.SignatureInfo
+	revealed   int
+}
+
+func loadFixtures(dir string) (*fixtures, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "signatures.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signatures: %w", err)
+	}
+
+	var newestFirst []buybot.SignatureInfo
+	if err := json.Unmarshal(data, &newestFirst); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal signatures: %w", err)
+	}
+
+	signatures := make([]buybot.SignatureInfo, 0, len(newestFirst)+1)
+	signatures = append(signatures, buybot.SignatureInfo{Signature: anchorSignature})
+	for i := len(newestFirst) - 1; i >= 0; i-- {
+		signatures = append(signatures, newestFirst[i])
+	}
+
+	return &fixtures{
+		dir:        dir,
+		signatures: signatures,
+		revealed:   1,
+	}, nil
+}
+
+func (f *fixtures) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ID     uint64            `json:"id"`
+		Method string            `json:"method"`
+		Params []json.RawMessage `json:"params"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+
+	var result interface{}
+	switch req.Method {
+	case "getSignaturesForAddress":
+		var opts struct {
+			Limit  int    `json:"limit"`
+			Before string `json:"before"`
+			Until  string `json:"until"`
+		}
+		if len(req.Params) > 1 {
+			json.Unmarshal(req.Params[1], &opts)
+		}
+		result = f.visibleSignatures(opts.Limit, opts.Before, opts.Until)
+	case "getTransaction":
+		var signature string
+		if len(req.Params) > 0 {
+			json.Unmarshal(req.Params[0], &signature)
+		}
+		data, err := os.ReadFile(filepath.Join(f.dir, "transactions", signature+".json"))
+		if err != nil {
+			result = nil
+		} else {
+			result = json.RawMessage(data)
+		}
+	default:
+		http.Error(w, "unsupported method: "+req.Method, http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      req.ID,
+		"result":  result,
+	})
+}
+
+func (f *fixtures) visibleSignatures(limit int, before string, until string) []buybot.SignatureInfo {
+	signatures := []buybot.SignatureInfo{}
+	started := before == ""
+
+	for i := f.revealed - 1; i >= 0; i-- {
+		sig := f.signatures[i]
+
+		if !started {
+			started = sig.Signature == before
+			continue
+		}
+
+		if sig.Signature == until || (limit > 0 && len(signatures) >= limit) {
+			break
+		}
+
+		signatures = append(signatures, sig)
+	}
+
+	return signatures
+}
+
+func (f *fixtures) revealUntil(t time.Time) {
+	for f.revealed < len(f.signatures) {
+		blockTime := f.signatures[f.revealed].BlockTime
+		if blockTime != nil && time.Unix(*blockTime, 0).After(t) {
+			return
+		}
+		f.revealed++
+	}
+}
+
+func (f *fixtures) startTime() time.Time {
+	for _, sig := range f.signatures {
+		if sig.BlockTime != nil {
+			return time.Unix(*sig.BlockTime, 0)
+		}
+	}
+	return time.Now()
+}
+
+func parseVenues(value string) ([]string, error) {
+	var venues []string
+	for _, venue := range strings.Split(strings.ToLower(value), ",") {
+		venue = strings.TrimSpace(venue)
+		if venue == "" {
+			continue
+		}
+		if !model.IsKnownVenue(venue) {
+			return nil, fmt.Errorf("unknown venue %s, known venues: %s", venue, strings.Join(model.Venues, ", "))
+		}
+		venues = append(venues, venue)
+	}
+	return venues, nil
+}
+
+func main() {
+	fixturesDir := flag.String("fixtures", "./fixtures/buybot", "directory with signatures.json and transactions/<signature>.json")
+	tokenAddress := flag.String("token", os.Getenv("TOKEN_ADDRESS"), "token mint to replay buys for")
+	ticker := flag.String("ticker", "TOKEN", "token ticker used in alerts")
+	solPrice := flag.Float64("sol-price", 0, "SOL price in USD used for alert values")
+	interval := flag.Duration("interval", buybot.PollInterval, "simulated poll interval")
+	minSol := flag.Float64("min-sol", 0, "minimum buy in SOL, as set with /set min_buy")
+	minUSD := flag.Float64("min-usd", 0, "minimum buy in USD, needs -sol-price")
+	venuesFlag := flag.String("venues", "", "comma-separated venues to alert on, as set with /set venues")
+	newHoldersOnly := flag.Bool("new-holders-only", false, "only alert on buys by new holders")
+	flag.Parse()
+
+	if *tokenAddress == "" {
+		fmt.Println("Token address is required, use -token or TOKEN_ADDRESS.")
+		os.Exit(1)
+	}
+
+	venues, err := parseVenues(*venuesFlag)
+	if err != nil {
+		fmt.Printf("Invalid venues: %v\n", err)
+		os.Exit(1)
+	}
+
+	f, err := loadFixtures(*fixturesDir)
+	if err != nil {
+		fmt.Printf("Failed to load fixtures: %v\n", err)
+		os.Exit(1)
+	}
+
+	storePath, err := os.MkdirTemp("", "buybot-replay-")
+	if err != nil {
+		fmt.Printf("Failed to create temporary store: %v\n", err)
+		os.Exit(1)
+	}
+	defer os.RemoveAll(storePath)
+
+	storeInstance, err := store.New(storePath, true, false)
+	if err != nil {
+		fmt.Printf("Failed to open temporary store: %v\n", err)
+		os.Exit(1)
+	}
+	storeInstance.MakeGlobal()
+
+	server := httptest.NewServer(f)
+	defer server.Close()
+
+	loggerInstance := logger.New()
+	cfg := &config.Config{
+		TokenAddress: *tokenAddress,
+		TokenTicker:  *ticker,
+	}
+	recipient := &model.Recipient{
+		MinBuySol:      *minSol,
+		MinBuyUSD:      *minUSD,
+		Venues:         venues,
+		NewHoldersOnly: *newHoldersOnly,
+	}
+
+	signalSender := buybot.NewSignalSender(nil, loggerInstance, cfg)
+	if *solPrice > 0 {
+		signalSender.SetPriceOracle(&buybot.StaticPriceOracle{SolUSD: *solPrice})
+	}
+
+	now := f.startTime()
+	signalSender.SetClock(func() time.Time {
+		return now
+	})
+	var detected []*buybot.BuyTransaction
+
+	monitor := buybot.NewMonitor(buybot.NewHeliusClient([]string{server.URL}, loggerInstance), loggerInstance, *tokenAddress, len(f.signatures))
+	monitor.SetBuyBatchHandler(func(buyTxs []*buybot.BuyTransaction) {
+		detected = buyTxs
+	})
+	monitor.SetSellHandler(func(sellTx *buybot.SellTransaction) {
+		fmt.Printf("[%s] SELL %s\n%s\n\n", now.Format(time.DateTime), sellTx.Signature, signalSender.PreviewSellSignal(sellTx, recipient))
+	})
+
+	monitor.Poll()
+
+	for f.revealed < len(f.signatures) {
+		f.revealUntil(now)
+
+		detected = nil
+		monitor.Poll()
+
+		sent, skipped := signalSender.SelectBuySignal(recipient, detected)
+		for _, buyTx := range detected {
+			if buyTx == sent {
+				fmt.Printf("[%s] BUY %s -> sent\n%s\n\n", now.Format(time.DateTime), buyTx.Signature, signalSender.PreviewBuySignal(buyTx, recipient))
+			} else {
+				fmt.Printf("[%s] BUY %s -> skipped (%s)\n\n", now.Format(time.DateTime), buyTx.Signature, skipped[buyTx.Signature])
+			}
+		}
+
+		now = now.Add(*interval)
+	}
+}
@@ -5,8 +5,11 @@ import (
 	"consul-telegram-bot/internal/buybot"
 	"consul-telegram-bot/internal/commands"
 	"consul-telegram-bot/internal/config"
+	"consul-telegram-bot/internal/dexscreener"
+	"consul-telegram-bot/internal/gating"
 	"consul-telegram-bot/internal/logger"
 	"consul-telegram-bot/internal/metrics"
+	"consul-telegram-bot/internal/model"
 	"consul-telegram-bot/internal/router"
 	"consul-telegram-bot/internal/store"
 
@@ -28,8 +31,21 @@ func deleteServiceMessage(botInstance *bot.Bot, loggerInstance *logger.Logger, e
 	}
 }
 
-func startUpdatesListener(botInstance *bot.Bot, routerInstance *router.Router, loggerInstance *logger.Logger) {
+func handleUserJoined(botInstance *bot.Bot, loggerInstance *logger.Logger, gatekeeper *gating.Gatekeeper) func(telebot.Context) error {
+	deleteMessage := deleteServiceMessage(botInstance, loggerInstance, "user_joined")
+	return func(c telebot.Context) error {
+		if gatekeeper != nil && c.Message() != nil {
+			gatekeeper.HandleUserJoined(c.Message())
+		}
+		return deleteMessage(c)
+	}
+}
+
+func startUpdatesListener(botInstance *bot.Bot, routerInstance *router.Router, loggerInstance *logger.Logger, gatekeeper *gating.Gatekeeper) {
 	botInstance.Bot.Handle(telebot.OnText, func(c telebot.Context) error {
+		if gatekeeper != nil {
+			gatekeeper.TrackMessage(c.Message())
+		}
 		routerInstance.HandleTextMessage(c.Message())
 		return nil
 	})
@@ -39,7 +55,7 @@ func startUpdatesListener(botInstance *bot.Bot, routerInstance *router.Router, l
 		return nil
 	})
 
-	botInstance.Bot.Handle(telebot.OnUserJoined, deleteServiceMessage(botInstance, loggerInstance, "user_joined"))
+	botInstance.Bot.Handle(telebot.OnUserJoined, handleUserJoined(botInstance, loggerInstance, gatekeeper))
 	botInstance.Bot.Handle(telebot.OnUserLeft, deleteServiceMessage(botInstance, loggerInstance, "user_left"))
 	botInstance.Bot.Handle(telebot.OnAddedToGroup, deleteServiceMessage(botInstance, loggerInstance, "added_to_group"))
 }
@@ -59,6 +75,14 @@ func configureCommands(routerInstance *router.Router) {
 	routerInstance.AddCommand("/website", commands.Website)
 	routerInstance.AddCommand("/ca", commands.CA)
 	routerInstance.AddCommand("/chart", commands.Chart)
+	routerInstance.AddCommand("/volume", commands.Volume)
+	routerInstance.AddCommand("/topbuyers", commands.TopBuyers)
+	routerInstance.AddCommand("/holders", commands.Holders)
+	routerInstance.AddCommand("/link_wallet", commands.LinkWallet)
+	routerInstance.AddCommand("/verify_wallet", commands.VerifyWallet)
+	routerInstance.AddCommand("/wallets", commands.Wallets)
+	routerInstance.AddCommand("/unlink_wallet", commands.UnlinkWallet)
+	routerInstance.AddCommand("/gate_report", commands.GateReport)
 	routerInstance.AddCommand("/define_thread_id", commands.DefineThreadId)
 	routerInstance.AddCommand("/retransmit", commands.Retransmit)
 	routerInstance.AddCommand("/setup", commands.Setup)
@@ -69,6 +93,22 @@ func configureCommands(routerInstance *router.Router) {
 	routerInstance.AddCommand("/set_llm_context", commands.SetLLMContext)
 	routerInstance.AddCommand("/up", commands.Up)
 	routerInstance.AddCommand("/leaderboard", commands.Leaderboard)
+	routerInstance.AddCommand("/add_gif", commands.AddGif)
+	routerInstance.AddCommand("/gifs", commands.Gifs)
+	routerInstance.AddCommand("/remove_gif", commands.RemoveGif)
+	routerInstance.AddCommand("/reset_gifs", commands.ResetGifs)
+	routerInstance.AddCommand("/set_buy_template", commands.SetBuyTemplate)
+	routerInstance.AddCommand("/preview_buy", commands.PreviewBuy)
+	routerInstance.AddCommand("/add_buy_button", commands.AddBuyButton)
+	routerInstance.AddCommand("/buy_buttons", commands.BuyButtons)
+	routerInstance.AddCommand("/remove_buy_button", commands.RemoveBuyButton)
+	routerInstance.AddCommand("/label_wallet", commands.LabelWallet)
+	routerInstance.AddCommand("/ignore_wallet", commands.IgnoreWallet)
+	routerInstance.AddCommand("/unlabel_wallet", commands.UnlabelWallet)
+	routerInstance.AddCommand("/wallet_labels", commands.WalletLabels)
+	routerInstance.AddCommand("/watch_wallet", commands.WatchWallet)
+	routerInstance.AddCommand("/unwatch_wallet", commands.UnwatchWallet)
+	routerInstance.AddCommand("/watched_wallets", commands.WatchedWallets)
 
 	routerInstance.LinkingButton("Help", "/help")
 	routerInstance.LinkingButton("Id", "/id")
@@ -106,23 +146,52 @@ func main() {
 	configureCommands(routerInstance)
 	configureKeyboard(botInstance)
 
-	go startUpdatesListener(botInstance, routerInstance, loggerInstance)
+	var gatekeeper *gating.Gatekeeper
 
-	if configInstance.HeliusRpcURL != "" && configInstance.TokenAddress != "" {
-		loggerInstance.Info("starting Consul buy bot...")
-		heliusClient := buybot.NewHeliusClient(configInstance.HeliusRpcURL)
-		monitor := buybot.NewMonitor(heliusClient, loggerInstance, configInstance.TokenAddress)
+	if configInstance.HeliusRpcURL != "" {
+		loggerInstance.Info("starting Consul buy bot in %s mode...", configInstance.BuyBotMode)
+		heliusClient := buybot.NewHeliusClient(configInstance.RpcURLs(), loggerInstance)
+		routerInstance.SetHeliusClient(heliusClient)
 		signalSender := buybot.NewSignalSender(botInstance, loggerInstance, configInstance)
+		signalSender.SetPriceOracle(buybot.NewDexscreenerOracle(dexscreener.NewClient(configInstance.DexscreenerAPIURL)))
+		registry := buybot.NewRegistry(heliusClient, loggerInstance, configInstance, signalSender)
 
-		monitor.SetBuyHandler(func(buyTx *buybot.BuyTransaction) {
-			signalSender.SendBuySignal(buyTx)
+		switch configInstance.BuyBotMode {
+		case buybot.ModeWebhook:
+			if configInstance.HeliusWebhookSecret == "" {
+				panic("HELIUS_WEBHOOK_SECRET is required when BUYBOT_MODE is webhook")
+			}
+			metrics.RegisterHandler(buybot.WebhookPath, buybot.NewWebhookHandler(registry, loggerInstance, configInstance.HeliusWebhookSecret))
+			loggerInstance.Info("listening for Helius webhooks on %s", buybot.WebhookPath)
+		case buybot.ModePolling:
+			if configInstance.HeliusWsURL != "" {
+				loggerInstance.Info("streaming buy detection enabled, polling is used as a fallback")
+				registry.SetStreamClient(buybot.NewStreamClient(configInstance.HeliusWsURL))
+			}
+		default:
+			panic("unknown BUYBOT_MODE: " + configInstance.BuyBotMode)
+		}
+
+		model.OnRecipientsChanged(registry.Sync)
+		registry.Sync()
+
+		go signalSender.RunDigests()
+		go registry.RunHistoryCleanup()
+		go registry.RunHolderSnapshots()
+		go buybot.NewWalletWatcher(heliusClient, loggerInstance, configInstance, signalSender).Run()
+
+		gatekeeper = gating.New(botInstance, heliusClient, loggerInstance, configInstance)
+		model.OnWalletsChanged(func(userID int64) {
+			go gatekeeper.HandleWalletsChanged(userID)
 		})
+		go gatekeeper.Run()
 
-		go monitor.Start()
 		loggerInstance.Info("Consul buy bot started successfully")
 	} else {
-		loggerInstance.Info("Helius RPC URL or Token Address not configured, buy bot disabled")
+		loggerInstance.Info("Helius RPC URL not configured, buy bot disabled")
 	}
 
+	go startUpdatesListener(botInstance, routerInstance, loggerInstance, gatekeeper)
+
 	botInstance.Start(8)
 }
@@ -0,0 +1,76 @@
+package buybot
+
+import (
+	"math/big"
+)
+
+type ownerTokenBalance struct {
+	Owner    string
+	Pre      *big.Int
+	Post     *big.Int
+	Decimals int
+}
+
+func (b *ownerTokenBalance) Delta() *big.Int {
+	return new(big.Int).Sub(b.Post, b.Pre)
+}
+
+func tokenBalancesByOwner(meta *TransactionMeta, mint string) []*ownerTokenBalance {
+	var balances []*ownerTokenBalance
+	byOwner := make(map[string]*ownerTokenBalance)
+
+	get := func(owner string, decimals int) *ownerTokenBalance {
+		balance, ok := byOwner[owner]
+		if !ok {
+			balance = &ownerTokenBalance{
+				Owner:    owner,
+				Pre:      new(big.Int),
+				Post:     new(big.Int),
+				Decimals: decimals,
+			}
+			byOwner[owner] = balance
+			balances = append(balances, balance)
+		}
+		return balance
+	}
+
+	for _, postBalance := range meta.PostTokenBalances {
+		if postBalance.Mint != mint {
+			continue
+		}
+
+		if amount, ok := parseRawAmount(postBalance.UiTokenAmount.Amount); ok {
+			balance := get(postBalance.Owner, postBalance.UiTokenAmount.Decimals)
+			balance.Post.Add(balance.Post, amount)
+		}
+	}
+
+	for _, preBalance := range meta.PreTokenBalances {
+		if preBalance.Mint != mint {
+			continue
+		}
+
+		if amount, ok := parseRawAmount(preBalance.UiTokenAmount.Amount); ok {
+			balance := get(preBalance.Owner, preBalance.UiTokenAmount.Decimals)
+			balance.Pre.Add(balance.Pre, amount)
+		}
+	}
+
+	return balances
+}
+
+func parseRawAmount(amount string) (*big.Int, bool) {
+	if amount == "" {
+		return nil, false
+	}
+	return new(big.Int).SetString(amount, 10)
+}
+
+func percentOf(part *big.Int, whole *big.Int) float64 {
+	if whole.Sign() == 0 {
+		return 0
+	}
+
+	percent, _ := new(big.Rat).SetFrac(new(big.Int).Mul(part, big.NewInt(100)), whole).Float64()
+	return percent
+}
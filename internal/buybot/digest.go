@@ -0,0 +1,186 @@
+package buybot
+
+import (
+	"consul-telegram-bot/internal/model"
+	"consul-telegram-bot/internal/utils"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	telebot "gopkg.in/telebot.v3"
+)
+
+const (
+	digestCheckInterval = 15 * time.Second
+	digestTopBuys       = 3
+)
+
+type buyDigest struct {
+	mint      string
+	startedAt time.Time
+	buys      []*BuyTransaction
+}
+
+func (s *SignalSender) CollectDigestBuys(buyTxs []*BuyTransaction) {
+	if len(buyTxs) == 0 {
+		return
+	}
+
+	recipients, err := s.getAllRecipients()
+	if err != nil {
+		s.logger.Error("failed to get recipients: %s", err)
+		return
+	}
+
+	var solPrice float64
+	if s.priceOracle != nil {
+		solPrice, _ = s.priceOracle.SolPrice()
+	}
+
+	s.digestMu.Lock()
+	defer s.digestMu.Unlock()
+
+	for _, recipient := range recipients {
+		if recipient.BuyDigestMinutes == 0 || recipient.GetThreadIdForSignalType(model.SignalTypeBuys) == 0 {
+			continue
+		}
+
+		for _, buyTx := range buyTxs {
+			if model.GetWithFallback(recipient.TokenAddress, s.config.TokenAddress) != buyTx.Mint {
+				continue
+			}
+
+			if s.buySkipReason(recipient, buyTx, &buyValuation{USD: buyTx.USDValue(solPrice)}) != "" {
+				continue
+			}
+
+			digest, ok := s.digests[recipient.Id]
+			if !ok || digest.mint != buyTx.Mint {
+				digest = &buyDigest{
+					mint:      buyTx.Mint,
+					startedAt: s.now(),
+				}
+				s.digests[recipient.Id] = digest
+			}
+
+			digest.buys = append(digest.buys, buyTx)
+		}
+	}
+}
+
+func (s *SignalSender) RunDigests() {
+	ticker := time.NewTicker(digestCheckInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		s.flushDigests()
+	}
+}
+
+func (s *SignalSender) flushDigests() {
+	s.digestMu.Lock()
+	due := make(map[int64]*buyDigest)
+	for chatID, digest := range s.digests {
+		recipient, err := model.FindRecipient(chatID)
+		if err != nil || recipient.BuyDigestMinutes == 0 {
+			delete(s.digests, chatID)
+			continue
+		}
+
+		window := time.Duration(recipient.BuyDigestMinutes) * time.Minute
+		if s.now().Sub(digest.startedAt) >= window {
+			due[chatID] = digest
+			delete(s.digests, chatID)
+		}
+	}
+	s.digestMu.Unlock()
+
+	for chatID, digest := range due {
+		recipient, err := model.FindRecipient(chatID)
+		if err != nil {
+			continue
+		}
+
+		threadId := recipient.GetThreadIdForSignalType(model.SignalTypeBuys)
+		if threadId == 0 {
+			continue
+		}
+
+		ticker := model.GetWithFallback(recipient.TokenTicker, s.config.TokenTicker)
+
+		message := s.formatDigestMessage(digest, ticker, recipient)
+
+		s.logger.Info("sending buy digest with %d buys to chat %d, thread %d", len(digest.buys), recipient.Id, threadId)
+
+		opts := &telebot.SendOptions{
+			ParseMode:             "HTML",
+			ThreadID:              threadId,
+			ReplyMarkup:           s.buyKeyboard(recipient, nil),
+			DisableWebPagePreview: true,
+		}
+
+		if _, err := s.bot.Bot.Send(recipient, message, opts); err != nil {
+			s.logger.Error("failed to send buy digest to chat %d: %s", recipient.Id, err)
+		}
+	}
+}
+
+func (s *SignalSender) formatDigestMessage(digest *buyDigest, ticker string, recipient *model.Recipient) string {
+	explorer := recipient.GetExplorer()
+
+	if ticker == "" {
+		ticker = "TOKEN"
+	}
+
+	var totalSol float64
+	var decimals int
+	totalTokens := new(big.Int)
+	buyers := make(map[string]bool)
+
+	for _, buyTx := range digest.buys {
+		totalSol += buyTx.SolAmount
+		totalTokens.Add(totalTokens, buyTx.Amount)
+		decimals = buyTx.Decimals
+		buyers[buyTx.Buyer] = true
+	}
+
+	totalSpent := utils.FormatNumber(totalSol, "SOL")
+	if s.priceOracle != nil {
+		if solPrice, err := s.priceOracle.SolPrice(); err == nil {
+			totalSpent += " (" + utils.FormatUSD(totalSol*solPrice) + ")"
+		}
+	}
+
+	topBuys := append([]*BuyTransaction(nil), digest.buys...)
+	sort.Slice(topBuys, func(i, j int) bool {
+		return topBuys[i].SolAmount > topBuys[j].SolAmount
+	})
+	if len(topBuys) > digestTopBuys {
+		topBuys = topBuys[:digestTopBuys]
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("<b>$%s BUYS IN THE LAST %d MIN %s</b>\n\n", ticker, recipient.BuyDigestMinutes, model.DefaultBuyTiers[0].Emoji))
+	sb.WriteString(fmt.Sprintf("<b>🛒 Buys:</b> %d\n", len(digest.buys)))
+	sb.WriteString(fmt.Sprintf("<b>💵 Total spent:</b> %s\n", totalSpent))
+	sb.WriteString(fmt.Sprintf("<b>💰 Total bought:</b> %s\n", utils.FormatTokenAmount(totalTokens, decimals, ticker)))
+	sb.WriteString(fmt.Sprintf("<b>🦊 Unique buyers:</b> %d\n\n", len(buyers)))
+	sb.WriteString("<b>Top buys:</b>\n")
+
+	for i, buyTx := range topBuys {
+		sb.WriteString(fmt.Sprintf(
+			"%d. <a href=\"%s\">%s</a> for %s by <a href=\"%s\">%s</a>\n",
+			i+1,
+			explorer.TxURL(buyTx.Signature),
+			buyTx.FormatSpent(),
+			utils.FormatTokenAmount(buyTx.Amount, buyTx.Decimals, ticker),
+			explorer.AccountURL(buyTx.Buyer),
+			utils.EscapeHTML(walletName(recipient, buyTx.Buyer)),
+		))
+	}
+
+	return strings.TrimSuffix(sb.String(), "\n")
+}
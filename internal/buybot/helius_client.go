@@ -2,28 +2,37 @@ package buybot
 
 import (
 	"bytes"
+	"consul-telegram-bot/internal/logger"
+	"consul-telegram-bot/internal/metrics"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math/big"
 	"net/http"
+	"strconv"
+	"sync/atomic"
 	"time"
 )
 
 type HeliusClient struct {
-	rpcURL     string
+	endpoints  []*rpcEndpoint
+	logger     *logger.Logger
 	httpClient *http.Client
+	nextID     atomic.Uint64
 }
 
 type RPCRequest struct {
-	Jsonrpc string        `json:"jsonrpc"`
-	ID      int           `json:"id"`
-	Method  string        `json:"method"`
-	Params  []interface{} `json:"params"`
+	Jsonrpc string      `json:"jsonrpc"`
+	ID      uint64      `json:"id"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params"`
 }
 
 type RPCResponse struct {
 	Jsonrpc string          `json:"jsonrpc"`
-	ID      int             `json:"id"`
+	ID      uint64          `json:"id"`
 	Result  json.RawMessage `json:"result"`
 	Error   *RPCError       `json:"error,omitempty"`
 }
@@ -34,18 +43,24 @@ type RPCError struct {
 }
 
 type SignatureInfo struct {
-	Signature string `json:"signature"`
-	Slot      uint64 `json:"slot"`
+	Signature string      `json:"signature"`
+	Slot      uint64      `json:"slot"`
 	Err       interface{} `json:"err"`
-	Memo      *string `json:"memo"`
-	BlockTime *int64  `json:"blockTime"`
+	Memo      *string     `json:"memo"`
+	BlockTime *int64      `json:"blockTime"`
+}
+
+type SignaturesOptions struct {
+	Limit  int
+	Before string
+	Until  string
 }
 
 type TransactionResponse struct {
-	Slot        uint64                 `json:"slot"`
-	Transaction TransactionData        `json:"transaction"`
-	Meta        *TransactionMeta       `json:"meta"`
-	BlockTime   *int64                 `json:"blockTime"`
+	Slot        uint64           `json:"slot"`
+	Transaction TransactionData  `json:"transaction"`
+	Meta        *TransactionMeta `json:"meta"`
+	BlockTime   *int64           `json:"blockTime"`
 }
 
 type TransactionData struct {
@@ -54,25 +69,45 @@ type TransactionData struct {
 }
 
 type MessageData struct {
+	Header          MessageHeader `json:"header"`
 	AccountKeys     []string      `json:"accountKeys"`
 	Instructions    []Instruction `json:"instructions"`
 	RecentBlockhash string        `json:"recentBlockhash"`
 }
 
+type MessageHeader struct {
+	NumRequiredSignatures       int `json:"numRequiredSignatures"`
+	NumReadonlySignedAccounts   int `json:"numReadonlySignedAccounts"`
+	NumReadonlyUnsignedAccounts int `json:"numReadonlyUnsignedAccounts"`
+}
+
 type Instruction struct {
 	ProgramIdIndex int    `json:"programIdIndex"`
 	Accounts       []int  `json:"accounts"`
 	Data           string `json:"data"`
+	StackHeight    *int   `json:"stackHeight,omitempty"`
+}
+
+type InnerInstruction struct {
+	Index        int           `json:"index"`
+	Instructions []Instruction `json:"instructions"`
+}
+
+type LoadedAddresses struct {
+	Writable []string `json:"writable"`
+	Readonly []string `json:"readonly"`
 }
 
 type TransactionMeta struct {
-	Err               interface{}       `json:"err"`
-	Fee               uint64            `json:"fee"`
-	PreBalances       []uint64          `json:"preBalances"`
-	PostBalances      []uint64          `json:"postBalances"`
-	PreTokenBalances  []TokenBalance    `json:"preTokenBalances"`
-	PostTokenBalances []TokenBalance    `json:"postTokenBalances"`
-	LogMessages       []string          `json:"logMessages"`
+	Err               interface{}        `json:"err"`
+	Fee               uint64             `json:"fee"`
+	PreBalances       []uint64           `json:"preBalances"`
+	PostBalances      []uint64           `json:"postBalances"`
+	PreTokenBalances  []TokenBalance     `json:"preTokenBalances"`
+	PostTokenBalances []TokenBalance     `json:"postTokenBalances"`
+	LogMessages       []string           `json:"logMessages"`
+	InnerInstructions []InnerInstruction `json:"innerInstructions"`
+	LoadedAddresses   *LoadedAddresses   `json:"loadedAddresses"`
 }
 
 type TokenBalance struct {
@@ -84,25 +119,86 @@ type TokenBalance struct {
 }
 
 type TokenAmount struct {
-	Amount         string  `json:"amount"`
-	Decimals       int     `json:"decimals"`
-	UiAmount       float64 `json:"uiAmount"`
-	UiAmountString string  `json:"uiAmountString"`
+	Amount         string   `json:"amount"`
+	Decimals       int      `json:"decimals"`
+	UiAmount       *float64 `json:"uiAmount"`
+	UiAmountString string   `json:"uiAmountString"`
 }
 
-func NewHeliusClient(rpcURL string) *HeliusClient {
+func (tx *TransactionResponse) AccountKeys() []string {
+	keys := tx.Transaction.Message.AccountKeys
+	if tx.Meta == nil || tx.Meta.LoadedAddresses == nil {
+		return keys
+	}
+
+	all := make([]string, 0, len(keys)+len(tx.Meta.LoadedAddresses.Writable)+len(tx.Meta.LoadedAddresses.Readonly))
+	all = append(all, keys...)
+	all = append(all, tx.Meta.LoadedAddresses.Writable...)
+	all = append(all, tx.Meta.LoadedAddresses.Readonly...)
+
+	return all
+}
+
+func (tx *TransactionResponse) IsSigner(account string) bool {
+	keys := tx.Transaction.Message.AccountKeys
+	for i := 0; i < tx.Transaction.Message.Header.NumRequiredSignatures && i < len(keys); i++ {
+		if keys[i] == account {
+			return true
+		}
+	}
+	return false
+}
+
+func NewHeliusClient(rpcURLs []string, logger *logger.Logger) *HeliusClient {
+	endpoints := make([]*rpcEndpoint, 0, len(rpcURLs))
+	for _, rpcURL := range rpcURLs {
+		if rpcURL != "" {
+			endpoints = append(endpoints, newRPCEndpoint(rpcURL))
+		}
+	}
+
 	return &HeliusClient{
-		rpcURL: rpcURL,
+		endpoints: endpoints,
+		logger:    logger,
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
 	}
 }
 
-func (c *HeliusClient) call(method string, params []interface{}) (json.RawMessage, error) {
+var ErrDASUnavailable = errors.New("no Helius RPC endpoint configured for DAS methods")
+
+func (c *HeliusClient) call(ctx context.Context, method string, params interface{}) (json.RawMessage, error) {
+	if len(c.endpoints) == 0 {
+		return nil, fmt.Errorf("no RPC endpoints configured")
+	}
+
+	return c.callEndpoints(ctx, c.endpoints, method, params)
+}
+
+// callDAS sends a Helius DAS method to the Helius endpoints only, so a
+// failover never sends it to a provider that does not implement it.
+func (c *HeliusClient) callDAS(ctx context.Context, method string, params interface{}) (json.RawMessage, error) {
+	var endpoints []*rpcEndpoint
+	for _, endpoint := range c.endpoints {
+		if endpoint.das {
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+
+	if len(endpoints) == 0 {
+		return nil, ErrDASUnavailable
+	}
+
+	return c.callEndpoints(ctx, endpoints, method, params)
+}
+
+func (c *HeliusClient) callEndpoints(ctx context.Context, endpoints []*rpcEndpoint, method string, params interface{}) (json.RawMessage, error) {
+	requestID := c.nextID.Add(1)
+
 	reqBody := RPCRequest{
 		Jsonrpc: "2.0",
-		ID:      1,
+		ID:      requestID,
 		Method:  method,
 		Params:  params,
 	}
@@ -112,38 +208,124 @@ func (c *HeliusClient) call(method string, params []interface{}) (json.RawMessag
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	resp, err := c.httpClient.Post(c.rpcURL, "application/json", bytes.NewBuffer(jsonData))
+	var lastErr error
+	for attempt := 0; attempt < rpcMaxAttempts; attempt++ {
+		endpoint := pickEndpoint(endpoints)
+
+		result, retryAfter, err := c.send(ctx, endpoint, method, requestID, jsonData)
+		if err == nil {
+			endpoint.markSuccess()
+			return result, nil
+		}
+
+		lastErr = err
+
+		var rpcErr *rpcCallError
+		if !errors.As(err, &rpcErr) || !rpcErr.retryable {
+			return nil, err
+		}
+
+		endpoint.markFailure()
+
+		if attempt == rpcMaxAttempts-1 {
+			break
+		}
+
+		delay := rpcBackoff(attempt)
+		if retryAfter > delay {
+			delay = retryAfter
+		}
+
+		c.logger.Warning("RPC request %d (%s) to %s failed, retrying in %s: %s", requestID, method, endpoint.label, delay, err)
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+
+	return nil, fmt.Errorf("RPC request %d (%s) failed after %d attempts: %w", requestID, method, rpcMaxAttempts, lastErr)
+}
+
+func (c *HeliusClient) send(ctx context.Context, endpoint *rpcEndpoint, method string, requestID uint64, jsonData []byte) (json.RawMessage, time.Duration, error) {
+	startTime := time.Now()
+	defer func() {
+		metrics.RPCRequestDuration.WithLabelValues(method, endpoint.label).Observe(time.Since(startTime).Seconds())
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.url, bytes.NewReader(jsonData))
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
+		metrics.RPCErrorsTotal.WithLabelValues(method, endpoint.label, "transport").Inc()
+		if ctx.Err() != nil {
+			return nil, 0, ctx.Err()
+		}
+		return nil, 0, &rpcCallError{err: fmt.Errorf("failed to make request: %w", err), retryable: true}
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		metrics.RPCErrorsTotal.WithLabelValues(method, endpoint.label, "read_body").Inc()
+		return nil, 0, &rpcCallError{err: fmt.Errorf("failed to read response: %w", err), retryable: true}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		metrics.RPCErrorsTotal.WithLabelValues(method, endpoint.label, strconv.Itoa(resp.StatusCode)).Inc()
+		return nil, parseRetryAfter(resp.Header.Get("Retry-After")), &rpcCallError{
+			err:       fmt.Errorf("unexpected status code %d", resp.StatusCode),
+			retryable: resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError,
+		}
 	}
 
 	var rpcResp RPCResponse
 	if err := json.Unmarshal(body, &rpcResp); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+		metrics.RPCErrorsTotal.WithLabelValues(method, endpoint.label, "unmarshal").Inc()
+		return nil, 0, &rpcCallError{err: fmt.Errorf("failed to unmarshal response: %w", err), retryable: true}
+	}
+
+	if rpcResp.ID != requestID {
+		metrics.RPCErrorsTotal.WithLabelValues(method, endpoint.label, "id_mismatch").Inc()
+		return nil, 0, &rpcCallError{err: fmt.Errorf("response id %d does not match request id %d", rpcResp.ID, requestID), retryable: true}
 	}
 
 	if rpcResp.Error != nil {
-		return nil, fmt.Errorf("RPC error: %s", rpcResp.Error.Message)
+		metrics.RPCErrorsTotal.WithLabelValues(method, endpoint.label, "rpc_error").Inc()
+		return nil, 0, &rpcCallError{
+			err:       fmt.Errorf("RPC error %d: %s", rpcResp.Error.Code, rpcResp.Error.Message),
+			retryable: rpcResp.Error.Code == rpcCodeRateLimited,
+		}
 	}
 
-	return rpcResp.Result, nil
+	return rpcResp.Result, 0, nil
 }
 
-func (c *HeliusClient) GetSignaturesForAddress(address string, limit int) ([]SignatureInfo, error) {
+func (c *HeliusClient) GetSignaturesForAddress(ctx context.Context, address string, opts SignaturesOptions) ([]SignatureInfo, error) {
+	config := map[string]interface{}{
+		"limit":      opts.Limit,
+		"commitment": "confirmed",
+	}
+
+	if opts.Before != "" {
+		config["before"] = opts.Before
+	}
+
+	if opts.Until != "" {
+		config["until"] = opts.Until
+	}
+
 	params := []interface{}{
 		address,
-		map[string]interface{}{
-			"limit": limit,
-		},
+		config,
 	}
 
-	result, err := c.call("getSignaturesForAddress", params)
+	result, err := c.call(ctx, "getSignaturesForAddress", params)
 	if err != nil {
 		return nil, err
 	}
@@ -156,20 +338,25 @@ func (c *HeliusClient) GetSignaturesForAddress(address string, limit int) ([]Sig
 	return signatures, nil
 }
 
-func (c *HeliusClient) GetTransaction(signature string) (*TransactionResponse, error) {
+func (c *HeliusClient) GetTransaction(ctx context.Context, signature string) (*TransactionResponse, error) {
 	params := []interface{}{
 		signature,
 		map[string]interface{}{
 			"encoding":                       "json",
+			"commitment":                     "confirmed",
 			"maxSupportedTransactionVersion": 0,
 		},
 	}
 
-	result, err := c.call("getTransaction", params)
+	result, err := c.call(ctx, "getTransaction", params)
 	if err != nil {
 		return nil, err
 	}
 
+	if string(result) == "null" {
+		return nil, fmt.Errorf("transaction %s not found", signature)
+	}
+
 	var tx TransactionResponse
 	if err := json.Unmarshal(result, &tx); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal transaction: %w", err)
@@ -177,3 +364,188 @@ func (c *HeliusClient) GetTransaction(signature string) (*TransactionResponse, e
 
 	return &tx, nil
 }
+
+func (c *HeliusClient) GetTokenBalance(ctx context.Context, owner string, mint string) (*big.Int, int, error) {
+	params := []interface{}{
+		owner,
+		map[string]interface{}{
+			"mint": mint,
+		},
+		map[string]interface{}{
+			"encoding":   "jsonParsed",
+			"commitment": "confirmed",
+		},
+	}
+
+	result, err := c.call(ctx, "getTokenAccountsByOwner", params)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var accounts struct {
+		Value []struct {
+			Account struct {
+				Data struct {
+					Parsed struct {
+						Info struct {
+							TokenAmount TokenAmount `json:"tokenAmount"`
+						} `json:"info"`
+					} `json:"parsed"`
+				} `json:"data"`
+			} `json:"account"`
+		} `json:"value"`
+	}
+	if err := json.Unmarshal(result, &accounts); err != nil {
+		return nil, 0, fmt.Errorf("failed to unmarshal token accounts: %w", err)
+	}
+
+	balance := new(big.Int)
+	var decimals int
+
+	for _, account := range accounts.Value {
+		tokenAmount := account.Account.Data.Parsed.Info.TokenAmount
+		amount, ok := parseRawAmount(tokenAmount.Amount)
+		if !ok {
+			return nil, 0, fmt.Errorf("invalid token amount: %s", tokenAmount.Amount)
+		}
+
+		balance.Add(balance, amount)
+		decimals = tokenAmount.Decimals
+	}
+
+	return balance, decimals, nil
+}
+
+type TokenAccountBalance struct {
+	Address string
+	Amount  *big.Int
+}
+
+type AccountInfo struct {
+	Address string
+	Owner   string
+	Data    json.RawMessage
+}
+
+func (c *HeliusClient) GetTokenSupply(ctx context.Context, mint string) (*big.Int, int, error) {
+	result, err := c.call(ctx, "getTokenSupply", []interface{}{mint})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var supply struct {
+		Value TokenAmount `json:"value"`
+	}
+	if err := json.Unmarshal(result, &supply); err != nil {
+		return nil, 0, fmt.Errorf("failed to unmarshal token supply: %w", err)
+	}
+
+	amount, ok := parseRawAmount(supply.Value.Amount)
+	if !ok {
+		return nil, 0, fmt.Errorf("invalid token supply: %s", supply.Value.Amount)
+	}
+
+	return amount, supply.Value.Decimals, nil
+}
+
+func (c *HeliusClient) GetTokenLargestAccounts(ctx context.Context, mint string) ([]TokenAccountBalance, error) {
+	result, err := c.call(ctx, "getTokenLargestAccounts", []interface{}{mint})
+	if err != nil {
+		return nil, err
+	}
+
+	var largest struct {
+		Value []struct {
+			Address string `json:"address"`
+			Amount  string `json:"amount"`
+		} `json:"value"`
+	}
+	if err := json.Unmarshal(result, &largest); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal largest accounts: %w", err)
+	}
+
+	accounts := make([]TokenAccountBalance, 0, len(largest.Value))
+	for _, account := range largest.Value {
+		amount, ok := parseRawAmount(account.Amount)
+		if !ok {
+			return nil, fmt.Errorf("invalid token amount: %s", account.Amount)
+		}
+		accounts = append(accounts, TokenAccountBalance{Address: account.Address, Amount: amount})
+	}
+
+	return accounts, nil
+}
+
+func (c *HeliusClient) GetMultipleAccounts(ctx context.Context, addresses []string) ([]*AccountInfo, error) {
+	params := []interface{}{
+		addresses,
+		map[string]interface{}{
+			"encoding":   "jsonParsed",
+			"commitment": "confirmed",
+		},
+	}
+
+	result, err := c.call(ctx, "getMultipleAccounts", params)
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Value []*struct {
+			Owner string          `json:"owner"`
+			Data  json.RawMessage `json:"data"`
+		} `json:"value"`
+	}
+	if err := json.Unmarshal(result, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal accounts: %w", err)
+	}
+
+	accounts := make([]*AccountInfo, len(addresses))
+	for i, account := range response.Value {
+		if i >= len(addresses) || account == nil {
+			continue
+		}
+		accounts[i] = &AccountInfo{Address: addresses[i], Owner: account.Owner, Data: account.Data}
+	}
+
+	return accounts, nil
+}
+
+func (c *HeliusClient) CountTokenHolders(ctx context.Context, mint string, maxPages int) (int, bool, error) {
+	owners := make(map[string]bool)
+
+	for page := 1; page <= maxPages; page++ {
+		params := map[string]interface{}{
+			"mint":  mint,
+			"page":  page,
+			"limit": holderPageLimit,
+		}
+
+		result, err := c.callDAS(ctx, "getTokenAccounts", params)
+		if err != nil {
+			return 0, false, err
+		}
+
+		var response struct {
+			TokenAccounts []struct {
+				Owner  string      `json:"owner"`
+				Amount json.Number `json:"amount"`
+			} `json:"token_accounts"`
+		}
+		if err := json.Unmarshal(result, &response); err != nil {
+			return 0, false, fmt.Errorf("failed to unmarshal token accounts: %w", err)
+		}
+
+		for _, account := range response.TokenAccounts {
+			if amount, ok := parseRawAmount(account.Amount.String()); ok && amount.Sign() > 0 {
+				owners[account.Owner] = true
+			}
+		}
+
+		if len(response.TokenAccounts) < holderPageLimit {
+			return len(owners), true, nil
+		}
+	}
+
+	return len(owners), false, nil
+}
@@ -0,0 +1,56 @@
+package buybot
+
+import (
+	"consul-telegram-bot/internal/model"
+	"time"
+)
+
+const historyCleanupInterval = time.Hour
+
+func (r *Registry) recordBuys(buyTxs []*BuyTransaction) {
+	var solPrice float64
+	if r.signalSender.priceOracle != nil {
+		solPrice, _ = r.signalSender.priceOracle.SolPrice()
+	}
+
+	for _, buyTx := range buyTxs {
+		timestamp := buyTx.BlockTime
+		if timestamp == 0 {
+			timestamp = time.Now().Unix()
+		}
+
+		record := &model.BuyRecord{
+			Mint:      buyTx.Mint,
+			Signature: buyTx.Signature,
+			Buyer:     buyTx.Buyer,
+			Amount:    buyTx.Amount.String(),
+			Decimals:  buyTx.Decimals,
+			SolAmount: buyTx.SolAmount,
+			USDValue:  buyTx.USDValue(solPrice),
+			Timestamp: timestamp,
+		}
+
+		if err := record.Save(); err != nil {
+			r.logger.Error("failed to save buy %s: %s", buyTx.Signature, err)
+		}
+	}
+}
+
+func (r *Registry) RunHistoryCleanup() {
+	retention := time.Duration(r.config.BuyHistoryRetentionDays) * 24 * time.Hour
+
+	ticker := time.NewTicker(historyCleanupInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		deleted, err := model.DeleteOldBuyRecords(retention)
+		if err != nil {
+			r.logger.Error("failed to delete old buys: %s", err)
+			continue
+		}
+
+		if deleted > 0 {
+			r.logger.Info("deleted %d buys older than %d days", deleted, r.config.BuyHistoryRetentionDays)
+		}
+	}
+}
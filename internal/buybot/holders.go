@@ -0,0 +1,288 @@
+package buybot
+
+import (
+	"consul-telegram-bot/internal/model"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	holderPageLimit        = 1000
+	holderMaxPages         = 50
+	holderTopCount         = 10
+	holderSnapshotInterval = time.Hour
+	holderSnapshotTimeout  = 5 * time.Minute
+)
+
+var burnAddresses = map[string]bool{
+	"1nc1nerator11111111111111111111111111111111": true,
+	"11111111111111111111111111111111":            true,
+}
+
+var poolAuthorities = map[string]Venue{
+	"5Q544fKrFoe6tsEbD7S8EmxGTJYAKtTVhAW5Q5pge4j1": {Key: model.VenueRaydium, Name: "Raydium AMM"},
+	"GpMZbSM2GgvTKHJirzeGfMFoaZ8UR2X7F4v8vHW3sQY4": {Key: model.VenueRaydium, Name: "Raydium CPMM"},
+}
+
+type Holder struct {
+	Owner   string
+	Amount  *big.Int
+	Percent float64
+	Label   string
+	Pool    bool
+	Burn    bool
+}
+
+type HolderStats struct {
+	Mint            string
+	Supply          *big.Int
+	Decimals        int
+	Holders         int
+	HoldersComplete bool
+	HoldersKnown    bool
+	Top10Percent    float64
+	Largest         []*Holder
+}
+
+func LoadHolderStats(ctx context.Context, client *HeliusClient, mint string) (*HolderStats, error) {
+	supply, decimals, err := client.GetTokenSupply(ctx, mint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token supply: %w", err)
+	}
+
+	largest, err := largestHolders(ctx, client, mint)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &HolderStats{
+		Mint:     mint,
+		Supply:   supply,
+		Decimals: decimals,
+		Largest:  largest,
+	}
+
+	circulating := 0
+	for _, holder := range largest {
+		holder.Percent = percentOf(holder.Amount, supply)
+		if holder.Pool || holder.Burn || circulating == holderTopCount {
+			continue
+		}
+		stats.Top10Percent += holder.Percent
+		circulating++
+	}
+
+	if len(stats.Largest) > holderTopCount {
+		stats.Largest = stats.Largest[:holderTopCount]
+	}
+
+	holders, complete, err := client.CountTokenHolders(ctx, mint, holderMaxPages)
+	if err != nil {
+		client.logger.Warning("holder count of %s is unavailable: %s", mint, err)
+	} else {
+		stats.Holders = holders
+		stats.HoldersComplete = complete
+		stats.HoldersKnown = true
+	}
+
+	return stats, nil
+}
+
+type cachedHolderStats struct {
+	stats     *HolderStats
+	expiresAt time.Time
+}
+
+type HolderStatsCache struct {
+	ttl     time.Duration
+	entries map[string]cachedHolderStats
+	mu      sync.Mutex
+}
+
+func NewHolderStatsCache(ttl time.Duration) *HolderStatsCache {
+	return &HolderStatsCache{
+		ttl:     ttl,
+		entries: make(map[string]cachedHolderStats),
+	}
+}
+
+func (c *HolderStatsCache) Get(mint string, fetch func() (*HolderStats, error)) (*HolderStats, error) {
+	c.mu.Lock()
+	cached, ok := c.entries[mint]
+	c.mu.Unlock()
+
+	if ok && time.Now().Before(cached.expiresAt) {
+		return cached.stats, nil
+	}
+
+	stats, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.entries[mint] = cachedHolderStats{
+		stats:     stats,
+		expiresAt: time.Now().Add(c.ttl),
+	}
+	c.mu.Unlock()
+
+	return stats, nil
+}
+
+func SaveHolderSnapshot(stats *HolderStats, now time.Time) error {
+	if !stats.HoldersKnown {
+		return nil
+	}
+
+	snapshot := &model.HolderSnapshot{
+		Mint:            stats.Mint,
+		Date:            model.SnapshotDate(now),
+		Holders:         stats.Holders,
+		HoldersComplete: stats.HoldersComplete,
+		Supply:          stats.Supply.String(),
+		Decimals:        stats.Decimals,
+		Top10Percent:    stats.Top10Percent,
+		CreatedAt:       now.Unix(),
+	}
+
+	return snapshot.Save()
+}
+
+func (r *Registry) RunHolderSnapshots() {
+	ticker := time.NewTicker(holderSnapshotInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		today := model.SnapshotDate(time.Now())
+
+		for tokenAddress := range r.collectTokenAddresses() {
+			if _, err := model.FindHolderSnapshot(tokenAddress, today); err == nil {
+				continue
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), holderSnapshotTimeout)
+			stats, err := LoadHolderStats(ctx, r.client, tokenAddress)
+			cancel()
+
+			if err != nil {
+				r.logger.Error("failed to load holders of %s: %s", tokenAddress, err)
+				continue
+			}
+
+			if err := SaveHolderSnapshot(stats, time.Now()); err != nil {
+				r.logger.Error("failed to save holder snapshot of %s: %s", tokenAddress, err)
+				continue
+			}
+
+			r.logger.Info("saved holder snapshot of %s: %d holders", tokenAddress, stats.Holders)
+		}
+	}
+}
+
+func largestHolders(ctx context.Context, client *HeliusClient, mint string) ([]*Holder, error) {
+	largest, err := client.GetTokenLargestAccounts(ctx, mint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get largest accounts: %w", err)
+	}
+
+	if len(largest) == 0 {
+		return nil, nil
+	}
+
+	addresses := make([]string, len(largest))
+	for i, account := range largest {
+		addresses[i] = account.Address
+	}
+
+	tokenAccounts, err := client.GetMultipleAccounts(ctx, addresses)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token accounts: %w", err)
+	}
+
+	byOwner := make(map[string]*Holder)
+	var owners []string
+
+	for i, account := range largest {
+		owner := tokenAccountOwner(tokenAccounts[i])
+		if owner == "" {
+			owner = account.Address
+		}
+
+		holder, ok := byOwner[owner]
+		if !ok {
+			holder = &Holder{Owner: owner, Amount: new(big.Int)}
+			byOwner[owner] = holder
+			owners = append(owners, owner)
+		}
+		holder.Amount.Add(holder.Amount, account.Amount)
+	}
+
+	ownerAccounts, err := client.GetMultipleAccounts(ctx, owners)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get owner accounts: %w", err)
+	}
+
+	holders := make([]*Holder, 0, len(owners))
+	for i, owner := range owners {
+		holder := byOwner[owner]
+		labelHolder(holder, ownerAccounts[i])
+		holders = append(holders, holder)
+	}
+
+	sort.Slice(holders, func(i, j int) bool {
+		return holders[i].Amount.Cmp(holders[j].Amount) > 0
+	})
+
+	return holders, nil
+}
+
+func tokenAccountOwner(account *AccountInfo) string {
+	if account == nil {
+		return ""
+	}
+
+	var data struct {
+		Parsed struct {
+			Info struct {
+				Owner string `json:"owner"`
+			} `json:"info"`
+		} `json:"parsed"`
+	}
+	if err := json.Unmarshal(account.Data, &data); err != nil {
+		return ""
+	}
+
+	return data.Parsed.Info.Owner
+}
+
+func labelHolder(holder *Holder, ownerAccount *AccountInfo) {
+	if burnAddresses[holder.Owner] {
+		holder.Burn = true
+		holder.Label = "Burn"
+		return
+	}
+
+	if venue, ok := poolAuthorities[holder.Owner]; ok {
+		holder.Pool = true
+		holder.Label = venue.Name + " pool"
+		return
+	}
+
+	if ownerAccount != nil {
+		if venue, ok := venuePrograms[ownerAccount.Owner]; ok {
+			holder.Pool = true
+			holder.Label = venue.Name + " pool"
+			return
+		}
+	}
+
+	if wallet, err := model.FindUserWallet(holder.Owner); err == nil && wallet.Username != "" {
+		holder.Label = "@" + wallet.Username
+	}
+}
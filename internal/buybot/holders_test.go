@@ -0,0 +1,78 @@
+package buybot
+
+import (
+	"consul-telegram-bot/internal/logger"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestIsHeliusHost(t *testing.T) {
+	tests := []struct {
+		host string
+		want bool
+	}{
+		{host: "mainnet.helius-rpc.com", want: true},
+		{host: "rpc.helius.xyz", want: true},
+		{host: "api.mainnet-beta.solana.com"},
+		{host: "solana-mainnet.g.alchemy.com"},
+		{host: "127.0.0.1:8899"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			if got := isHeliusHost(tt.host); got != tt.want {
+				t.Errorf("isHeliusHost(%q) = %v, want %v", tt.host, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCountTokenHoldersUsesHeliusEndpointsOnly(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+
+		var request struct {
+			ID uint64 `json:"id"`
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      request.ID,
+			"result": map[string]interface{}{
+				"token_accounts": []map[string]interface{}{
+					{"owner": "A", "amount": 10},
+					{"owner": "A", "amount": 5},
+					{"owner": "B", "amount": 1},
+					{"owner": "C", "amount": 0},
+				},
+			},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewHeliusClient([]string{server.URL}, logger.New())
+
+	if _, _, err := client.CountTokenHolders(context.Background(), fixtureMint, 1); !errors.Is(err, ErrDASUnavailable) {
+		t.Fatalf("CountTokenHolders() on a plain RPC endpoint error = %v, want ErrDASUnavailable", err)
+	}
+	if requests.Load() != 0 {
+		t.Fatalf("plain RPC endpoint received %d DAS requests, want none", requests.Load())
+	}
+
+	client.endpoints[0].das = true
+
+	holders, complete, err := client.CountTokenHolders(context.Background(), fixtureMint, 1)
+	if err != nil {
+		t.Fatalf("CountTokenHolders() error = %v", err)
+	}
+	if holders != 2 || !complete {
+		t.Errorf("CountTokenHolders() = %d, %v, want 2, true", holders, complete)
+	}
+}
@@ -2,76 +2,194 @@ package buybot
 
 import (
 	"consul-telegram-bot/internal/logger"
-	"fmt"
+	"consul-telegram-bot/internal/model"
+	"context"
+	"math/big"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 const (
-	PollInterval = 10 * time.Second
+	PollInterval           = 10 * time.Second
+	signaturesPageSize     = 100
+	maxProcessedSignatures = 1000
+	streamMinBackoff       = time.Second
+	streamMaxBackoff       = time.Minute
+	streamReconcileEvery   = time.Minute
 )
 
 type BuyTransaction struct {
-	Signature string
-	Buyer     string
-	Amount    float64
-	SolAmount float64
-	BlockTime int64
-	TxURL     string
+	Mint             string
+	Signature        string
+	Buyer            string
+	Amount           *big.Int
+	Decimals         int
+	SolAmount        float64
+	BlockTime        int64
+	Slot             uint64
+	NewHolder        bool
+	PositionIncrease float64
+	QuoteSymbol      string
+	QuoteAmount      float64
+	Venue            *Venue
+	Router           *Venue
 }
 
 type Monitor struct {
-	client           *HeliusClient
-	logger           *logger.Logger
-	tokenAddress     string
-	lastSignature    string
-	mu               sync.RWMutex
-	onBuyTransaction func(*BuyTransaction)
-	processedSigs    map[string]bool
-	processedSigsMu  sync.RWMutex
-	lastSentTime     time.Time
-	lastSentAmount   float64
-	throttleMu       sync.RWMutex
+	client            *HeliusClient
+	streamClient      *StreamClient
+	logger            *logger.Logger
+	tokenAddress      string
+	backfillLimit     int
+	lastSignature     string
+	mu                sync.RWMutex
+	onBuyBatch        func([]*BuyTransaction)
+	onSellTransaction func(*SellTransaction)
+	priceOracle       PriceOracle
+	processedSigs     map[string]bool
+	processedOrder    []string
+	processedSigsMu   sync.RWMutex
+	processMu         sync.Mutex
+	streaming         atomic.Bool
+	ctx               context.Context
+	cancel            context.CancelFunc
 }
 
-func NewMonitor(client *HeliusClient, logger *logger.Logger, tokenAddress string) *Monitor {
+func NewMonitor(client *HeliusClient, logger *logger.Logger, tokenAddress string, backfillLimit int) *Monitor {
+	ctx, cancel := context.WithCancel(context.Background())
+
 	return &Monitor{
 		client:        client,
 		logger:        logger,
 		tokenAddress:  tokenAddress,
+		backfillLimit: backfillLimit,
 		processedSigs: make(map[string]bool),
+		ctx:           ctx,
+		cancel:        cancel,
 	}
 }
 
-func (m *Monitor) SetBuyHandler(handler func(*BuyTransaction)) {
-	m.onBuyTransaction = handler
+func (m *Monitor) SetBuyBatchHandler(handler func([]*BuyTransaction)) {
+	m.onBuyBatch = handler
+}
+
+func (m *Monitor) SetPriceOracle(priceOracle PriceOracle) {
+	m.priceOracle = priceOracle
+}
+
+func (m *Monitor) stableToSol(amount float64) float64 {
+	if m.priceOracle == nil {
+		return 0
+	}
+
+	solPrice, err := m.priceOracle.SolPrice()
+	if err != nil {
+		m.logger.Warning("failed to get SOL price: %s", err)
+		return 0
+	}
+
+	if solPrice == 0 {
+		return 0
+	}
+
+	return amount / solPrice
+}
+
+func (m *Monitor) SetStreamClient(streamClient *StreamClient) {
+	m.streamClient = streamClient
 }
 
 func (m *Monitor) Start() {
 	m.logger.Info("starting Consul buy monitor for token: %s", m.tokenAddress)
 
-	signatures, err := m.client.GetSignaturesForAddress(m.tokenAddress, 1)
-	if err != nil {
-		m.logger.Error("failed to get initial signatures: %s", err)
-	} else if len(signatures) > 0 {
-		m.mu.Lock()
-		m.lastSignature = signatures[0].Signature
-		m.mu.Unlock()
-		m.logger.Info("set initial signature: %s", m.lastSignature)
+	if !m.restoreCursor() {
+		m.initCursor()
+	}
+
+	if m.streamClient != nil {
+		go m.runStream()
 	}
 
 	ticker := time.NewTicker(PollInterval)
 	defer ticker.Stop()
 
-	for range ticker.C {
-		m.checkNewTransactions()
+	var lastPoll time.Time
+
+	for {
+		select {
+		case <-m.ctx.Done():
+			m.logger.Info("stopped Consul buy monitor for token: %s", m.tokenAddress)
+			return
+		case <-ticker.C:
+			if m.streaming.Load() && time.Since(lastPoll) < streamReconcileEvery {
+				continue
+			}
+			lastPoll = time.Now()
+			m.checkNewTransactions()
+		}
 	}
 }
 
-func (m *Monitor) checkNewTransactions() {
-	signatures, err := m.client.GetSignaturesForAddress(m.tokenAddress, 10)
+func (m *Monitor) Stop() {
+	m.cancel()
+}
+
+func (m *Monitor) runStream() {
+	backoff := streamMinBackoff
+
+	for {
+		err := m.streamClient.SubscribeLogs(m.tokenAddress, m.ctx.Done(), func() {
+			m.logger.Info("subscribed to logs stream for token: %s", m.tokenAddress)
+			m.streaming.Store(true)
+			backoff = streamMinBackoff
+			go m.checkNewTransactions()
+		}, m.handleStreamedLogs)
+
+		m.streaming.Store(false)
+
+		select {
+		case <-m.ctx.Done():
+			return
+		default:
+		}
+
+		m.logger.Warning("logs stream for token %s is down, falling back to polling (retry in %s): %s", m.tokenAddress, backoff, err)
+
+		select {
+		case <-m.ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > streamMaxBackoff {
+			backoff = streamMaxBackoff
+		}
+	}
+}
+
+func (m *Monitor) handleStreamedLogs(notification LogsNotification) {
+	m.processMu.Lock()
+	defer m.processMu.Unlock()
+
+	if m.isProcessed(notification.Signature) {
+		return
+	}
+
+	m.processSignatures([]SignatureInfo{
+		{
+			Signature: notification.Signature,
+			Slot:      notification.Slot,
+			Err:       notification.Err,
+		},
+	}, false)
+}
+
+func (m *Monitor) initCursor() {
+	signatures, err := m.client.GetSignaturesForAddress(m.ctx, m.tokenAddress, SignaturesOptions{Limit: 1})
 	if err != nil {
-		m.logger.Error("failed to get signatures: %s", err)
+		m.logger.Error("failed to get initial signatures: %s", err)
 		return
 	}
 
@@ -79,128 +197,219 @@ func (m *Monitor) checkNewTransactions() {
 		return
 	}
 
+	m.mu.Lock()
+	m.lastSignature = signatures[0].Signature
+	m.mu.Unlock()
+	m.logger.Info("set initial signature: %s", signatures[0].Signature)
+
+	m.saveCursor()
+}
+
+func (m *Monitor) restoreCursor() bool {
+	cursor, err := model.FindMonitorCursor(m.tokenAddress)
+	if err != nil {
+		return false
+	}
+
+	m.mu.Lock()
+	m.lastSignature = cursor.LastSignature
+	m.mu.Unlock()
+
+	for _, sig := range cursor.ProcessedSigs {
+		m.markProcessed(sig)
+	}
+
+	m.logger.Info("restored cursor for token %s: %s (%d processed signatures)", m.tokenAddress, cursor.LastSignature, len(cursor.ProcessedSigs))
+
+	return cursor.LastSignature != ""
+}
+
+func (m *Monitor) saveCursor() {
 	m.mu.RLock()
 	lastSig := m.lastSignature
 	m.mu.RUnlock()
 
-	var newSignatures []SignatureInfo
-	for _, sig := range signatures {
-		if sig.Signature == lastSig {
-			break
-		}
-		newSignatures = append(newSignatures, sig)
+	m.processedSigsMu.RLock()
+	processed := make([]string, len(m.processedOrder))
+	copy(processed, m.processedOrder)
+	m.processedSigsMu.RUnlock()
+
+	cursor := &model.MonitorCursor{
+		TokenAddress:  m.tokenAddress,
+		LastSignature: lastSig,
+		ProcessedSigs: processed,
 	}
 
-	if len(newSignatures) > 0 {
-		m.mu.Lock()
-		m.lastSignature = signatures[0].Signature
-		m.mu.Unlock()
+	if err := cursor.Save(); err != nil {
+		m.logger.Error("failed to save cursor for token %s: %s", m.tokenAddress, err)
+	}
+}
 
-		m.logger.Info("found %d new transactions", len(newSignatures))
+func (m *Monitor) isProcessed(signature string) bool {
+	m.processedSigsMu.RLock()
+	defer m.processedSigsMu.RUnlock()
+	return m.processedSigs[signature]
+}
 
-		var buyTransactions []*BuyTransaction
+func (m *Monitor) markProcessed(signature string) {
+	m.processedSigsMu.Lock()
+	defer m.processedSigsMu.Unlock()
 
-		for i := len(newSignatures) - 1; i >= 0; i-- {
-			sig := newSignatures[i]
+	if m.processedSigs[signature] {
+		return
+	}
 
-			m.processedSigsMu.RLock()
-			processed := m.processedSigs[sig.Signature]
-			m.processedSigsMu.RUnlock()
+	m.processedSigs[signature] = true
+	m.processedOrder = append(m.processedOrder, signature)
 
-			if processed {
-				continue
-			}
+	if len(m.processedOrder) > maxProcessedSignatures {
+		evicted := m.processedOrder[:len(m.processedOrder)-maxProcessedSignatures]
+		for _, sig := range evicted {
+			delete(m.processedSigs, sig)
+		}
+		m.processedOrder = append([]string(nil), m.processedOrder[len(evicted):]...)
+	}
+}
 
-			if sig.Err != nil {
-				m.processedSigsMu.Lock()
-				m.processedSigs[sig.Signature] = true
-				m.processedSigsMu.Unlock()
-				continue
-			}
+func (m *Monitor) fetchNewSignatures(until string) ([]SignatureInfo, error) {
+	var signatures []SignatureInfo
+	before := ""
 
-			buyTx := m.processTransaction(sig)
-			if buyTx != nil {
-				buyTransactions = append(buyTransactions, buyTx)
-			}
+	for len(signatures) < m.backfillLimit {
+		limit := signaturesPageSize
+		if remaining := m.backfillLimit - len(signatures); remaining < limit {
+			limit = remaining
+		}
 
-			m.processedSigsMu.Lock()
-			m.processedSigs[sig.Signature] = true
-			m.processedSigsMu.Unlock()
+		page, err := m.client.GetSignaturesForAddress(m.ctx, m.tokenAddress, SignaturesOptions{
+			Limit:  limit,
+			Before: before,
+			Until:  until,
+		})
+		if err != nil {
+			return nil, err
 		}
 
-		if len(buyTransactions) > 0 {
-			largestBuy := m.findLargestBuy(buyTransactions)
-			if largestBuy != nil && m.onBuyTransaction != nil {
-				if m.shouldSendBuy(largestBuy) {
-					m.logger.Info("sending largest buy from %d transactions: %.2f tokens", len(buyTransactions), largestBuy.Amount)
-					m.onBuyTransaction(largestBuy)
-
-					m.throttleMu.Lock()
-					m.lastSentTime = time.Now()
-					m.lastSentAmount = largestBuy.Amount
-					m.throttleMu.Unlock()
-				} else {
-					m.logger.Info("skipping buy notification (throttled): %.2f tokens", largestBuy.Amount)
-				}
-			}
+		signatures = append(signatures, page...)
+
+		if len(page) < limit {
+			return signatures, nil
 		}
-	}
 
-	m.processedSigsMu.Lock()
-	if len(m.processedSigs) > 1000 {
-		m.processedSigs = make(map[string]bool)
+		before = page[len(page)-1].Signature
 	}
-	m.processedSigsMu.Unlock()
+
+	m.logger.Warning("backfill limit of %d signatures reached for token %s, older signatures are skipped", m.backfillLimit, m.tokenAddress)
+
+	return signatures, nil
 }
 
-func (m *Monitor) processTransaction(sig SignatureInfo) *BuyTransaction {
-	tx, err := m.client.GetTransaction(sig.Signature)
+func (m *Monitor) Poll() {
+	m.checkNewTransactions()
+}
+
+func (m *Monitor) checkNewTransactions() {
+	m.processMu.Lock()
+	defer m.processMu.Unlock()
+
+	m.mu.RLock()
+	lastSig := m.lastSignature
+	m.mu.RUnlock()
+
+	if lastSig == "" {
+		m.initCursor()
+		return
+	}
+
+	newSignatures, err := m.fetchNewSignatures(lastSig)
 	if err != nil {
-		m.logger.Error("failed to get transaction %s: %s", sig.Signature, err)
-		return nil
+		m.logger.Error("failed to get signatures: %s", err)
+		return
 	}
 
-	if tx == nil || tx.Meta == nil {
-		return nil
+	if len(newSignatures) == 0 {
+		return
 	}
 
-	return m.analyzeBuyTransaction(tx, sig.Signature)
+	m.logger.Info("found %d new transactions", len(newSignatures))
+
+	m.processSignatures(newSignatures, true)
 }
 
-func (m *Monitor) findLargestBuy(buys []*BuyTransaction) *BuyTransaction {
-	if len(buys) == 0 {
-		return nil
-	}
+func (m *Monitor) processSignatures(newSignatures []SignatureInfo, advanceCursor bool) {
+	var buyTransactions []*BuyTransaction
+	var sellTransactions []*SellTransaction
+
+	cursor := ""
+	failed := false
 
-	largest := buys[0]
-	for _, buy := range buys[1:] {
-		if buy.Amount > largest.Amount {
-			largest = buy
+	for i := len(newSignatures) - 1; i >= 0; i-- {
+		sig := newSignatures[i]
+
+		if !m.isProcessed(sig.Signature) && sig.Err == nil {
+			buyTx, sellTx, err := m.processTransaction(sig)
+			if err != nil {
+				m.logger.Error("failed to get transaction %s, retrying on next poll: %s", sig.Signature, err)
+				failed = true
+				continue
+			}
+
+			if buyTx != nil {
+				buyTransactions = append(buyTransactions, buyTx)
+			}
+			if sellTx != nil {
+				sellTransactions = append(sellTransactions, sellTx)
+			}
+		}
+
+		m.markProcessed(sig.Signature)
+
+		if !failed {
+			cursor = sig.Signature
 		}
 	}
 
-	return largest
+	if advanceCursor && cursor != "" {
+		m.mu.Lock()
+		m.lastSignature = cursor
+		m.mu.Unlock()
+	}
+
+	m.saveCursor()
+
+	m.dispatchBuys(buyTransactions)
+	m.dispatchSells(sellTransactions)
 }
 
-func (m *Monitor) shouldSendBuy(buyTx *BuyTransaction) bool {
-	m.throttleMu.RLock()
-	defer m.throttleMu.RUnlock()
+func (m *Monitor) dispatchBuys(buyTransactions []*BuyTransaction) {
+	if len(buyTransactions) == 0 {
+		return
+	}
 
-	if m.lastSentTime.IsZero() {
-		return true
+	if m.onBuyBatch != nil {
+		m.onBuyBatch(buyTransactions)
 	}
+}
 
-	timeSinceLastSent := time.Since(m.lastSentTime)
+func (m *Monitor) processTransaction(sig SignatureInfo) (*BuyTransaction, *SellTransaction, error) {
+	tx, err := m.client.GetTransaction(m.ctx, sig.Signature)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	if timeSinceLastSent < time.Minute {
-		if buyTx.Amount > m.lastSentAmount {
-			m.logger.Info("new buy is larger (%.2f > %.2f), resetting timer", buyTx.Amount, m.lastSentAmount)
-			return true
-		}
-		return false
+	if tx.Meta == nil {
+		return nil, nil, nil
+	}
+
+	if buyTx := m.analyzeBuyTransaction(tx, sig.Signature); buyTx != nil {
+		return buyTx, nil, nil
 	}
 
-	return true
+	if m.onSellTransaction != nil {
+		return nil, m.analyzeSellTransaction(tx, sig.Signature), nil
+	}
+
+	return nil, nil, nil
 }
 
 func (m *Monitor) analyzeBuyTransaction(tx *TransactionResponse, signature string) *BuyTransaction {
@@ -208,59 +417,36 @@ func (m *Monitor) analyzeBuyTransaction(tx *TransactionResponse, signature strin
 		return nil
 	}
 
-	var buyer string
-	var tokenAmount float64
-	var solAmount float64
-	var foundBuyer bool
-
-	for _, postBalance := range tx.Meta.PostTokenBalances {
-		if postBalance.Mint != m.tokenAddress {
+	var buyerBalance *ownerTokenBalance
+	for _, balance := range tokenBalancesByOwner(tx.Meta, m.tokenAddress) {
+		if balance.Owner == "" || balance.Delta().Sign() <= 0 || !tx.IsSigner(balance.Owner) {
 			continue
 		}
 
-		var preAmount float64
-		for _, preBalance := range tx.Meta.PreTokenBalances {
-			if preBalance.AccountIndex == postBalance.AccountIndex {
-				preAmount = preBalance.UiTokenAmount.UiAmount
-				break
-			}
-		}
-
-		postAmount := postBalance.UiTokenAmount.UiAmount
-
-		if postAmount > preAmount {
-			tokenAmount = postAmount - preAmount
-			buyer = postBalance.Owner
-			foundBuyer = true
-			break
+		if buyerBalance == nil || balance.Delta().Cmp(buyerBalance.Delta()) > 0 {
+			buyerBalance = balance
 		}
 	}
 
-	if !foundBuyer || tokenAmount == 0 || buyer == "" {
+	if buyerBalance == nil {
 		return nil
 	}
 
-	if len(tx.Meta.PreBalances) > 0 && len(tx.Meta.PostBalances) > 0 {
-		preSol := float64(tx.Meta.PreBalances[0]) / 1e9
-		postSol := float64(tx.Meta.PostBalances[0]) / 1e9
-		fee := float64(tx.Meta.Fee) / 1e9
-
-		totalDecrease := preSol - postSol
-
-		solAmount = totalDecrease - fee
-		const minSolSpend = 0.01
+	buyer := buyerBalance.Owner
+	tokenAmount := buyerBalance.Delta()
 
-		if solAmount <= 0 {
-			m.logger.Info("skipping transaction %s: SOL balance did not decrease beyond fee (sell detected)", signature)
-			return nil
-		}
+	quote := buyerQuoteSpend(tx, buyer)
+	if quote.Amount <= 0 {
+		m.logger.Info("skipping transaction %s: buyer spent no SOL or stablecoins beyond fee (sell detected)", signature)
+		return nil
+	}
 
-		if solAmount < minSolSpend {
-			m.logger.Info("skipping transaction %s: SOL spend too low (%.6f SOL, likely arbitrage)", signature, solAmount)
+	if quote.Stable {
+		if quote.Amount < minStableSpend {
+			m.logger.Info("skipping transaction %s: %s spend too low (%.6f %s, likely arbitrage)", signature, quote.Symbol, quote.Amount, quote.Symbol)
 			return nil
 		}
-	} else {
-		return nil
+		quote.SolAmount = m.stableToSol(quote.Amount)
 	}
 
 	blockTime := int64(0)
@@ -268,12 +454,25 @@ func (m *Monitor) analyzeBuyTransaction(tx *TransactionResponse, signature strin
 		blockTime = *tx.BlockTime
 	}
 
-	return &BuyTransaction{
-		Signature: signature,
-		Buyer:     buyer,
-		Amount:    tokenAmount,
-		SolAmount: solAmount,
-		BlockTime: blockTime,
-		TxURL:     fmt.Sprintf("https://solscan.io/tx/%s", signature),
+	buyTx := &BuyTransaction{
+		Mint:        m.tokenAddress,
+		Signature:   signature,
+		Buyer:       buyer,
+		Amount:      tokenAmount,
+		Decimals:    buyerBalance.Decimals,
+		SolAmount:   quote.SolAmount,
+		QuoteSymbol: quote.Symbol,
+		QuoteAmount: quote.Amount,
+		BlockTime:   blockTime,
+		Slot:        tx.Slot,
+		NewHolder:   buyerBalance.Pre.Sign() == 0,
 	}
+
+	if !buyTx.NewHolder {
+		buyTx.PositionIncrease = percentOf(tokenAmount, buyerBalance.Pre)
+	}
+
+	buyTx.Venue, buyTx.Router = classifyVenue(tx)
+
+	return buyTx
 }
@@ -0,0 +1,117 @@
+package buybot
+
+import (
+	"consul-telegram-bot/internal/dexscreener"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	WrappedSolMint = "So11111111111111111111111111111111111111112"
+	priceCacheTTL  = 30 * time.Second
+)
+
+type TokenPrice struct {
+	PriceUSD float64
+	Supply   float64
+}
+
+func (p *TokenPrice) MarketCap() float64 {
+	return p.PriceUSD * p.Supply
+}
+
+type PriceOracle interface {
+	SolPrice() (float64, error)
+	TokenPrice(mint string) (*TokenPrice, error)
+}
+
+type StaticPriceOracle struct {
+	SolUSD float64
+	Tokens map[string]TokenPrice
+}
+
+func (o *StaticPriceOracle) SolPrice() (float64, error) {
+	if o.SolUSD == 0 {
+		return 0, fmt.Errorf("SOL price is not set")
+	}
+	return o.SolUSD, nil
+}
+
+func (o *StaticPriceOracle) TokenPrice(mint string) (*TokenPrice, error) {
+	price, ok := o.Tokens[mint]
+	if !ok {
+		return nil, fmt.Errorf("price for %s is not set", mint)
+	}
+	return &price, nil
+}
+
+type cachedPrice struct {
+	price     TokenPrice
+	expiresAt time.Time
+}
+
+type DexscreenerOracle struct {
+	client *dexscreener.Client
+	cache  map[string]cachedPrice
+	now    func() time.Time
+	mu     sync.Mutex
+}
+
+func NewDexscreenerOracle(client *dexscreener.Client) *DexscreenerOracle {
+	return &DexscreenerOracle{
+		client: client,
+		cache:  make(map[string]cachedPrice),
+		now:    time.Now,
+	}
+}
+
+func (o *DexscreenerOracle) SetClock(now func() time.Time) {
+	o.now = now
+}
+
+func (o *DexscreenerOracle) SolPrice() (float64, error) {
+	price, err := o.TokenPrice(WrappedSolMint)
+	if err != nil {
+		return 0, err
+	}
+	return price.PriceUSD, nil
+}
+
+func (o *DexscreenerOracle) TokenPrice(mint string) (*TokenPrice, error) {
+	o.mu.Lock()
+	cached, ok := o.cache[mint]
+	o.mu.Unlock()
+
+	if ok && o.now().Before(cached.expiresAt) {
+		price := cached.price
+		return &price, nil
+	}
+
+	pairs, err := o.client.GetTokenPairs("solana", mint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pairs for %s: %w", mint, err)
+	}
+
+	pair := dexscreener.MostLiquidPair(pairs, mint)
+	if pair == nil {
+		return nil, fmt.Errorf("no priced pairs found for %s", mint)
+	}
+
+	price := TokenPrice{
+		PriceUSD: pair.PriceUSD(),
+	}
+
+	if pair.Fdv > 0 {
+		price.Supply = pair.Fdv / price.PriceUSD
+	}
+
+	o.mu.Lock()
+	o.cache[mint] = cachedPrice{
+		price:     price,
+		expiresAt: o.now().Add(priceCacheTTL),
+	}
+	o.mu.Unlock()
+
+	return &price, nil
+}
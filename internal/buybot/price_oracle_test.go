@@ -0,0 +1,112 @@
+package buybot
+
+import (
+	"consul-telegram-bot/internal/dexscreener"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newFakeDexscreener(t *testing.T, routes map[string]string) (*dexscreener.Client, *atomic.Int32) {
+	t.Helper()
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+
+		body, ok := routes[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, body)
+	}))
+	t.Cleanup(server.Close)
+
+	return dexscreener.NewClient(server.URL), &requests
+}
+
+func TestDexscreenerOracleTokenPrice(t *testing.T) {
+	client, _ := newFakeDexscreener(t, map[string]string{
+		"/token-pairs/v1/solana/Mint": `[
+			{"pairAddress":"Small","baseToken":{"address":"Mint"},"priceUsd":"0.5","fdv":1000,"liquidity":{"usd":1000}},
+			{"pairAddress":"Quote","baseToken":{"address":"Other"},"priceUsd":"9","fdv":900000,"liquidity":{"usd":900000}},
+			{"pairAddress":"Large","baseToken":{"address":"Mint"},"priceUsd":"0.02","fdv":2000000,"liquidity":{"usd":80000}}
+		]`,
+		"/token-pairs/v1/solana/NoFdv":             `[{"pairAddress":"Pair","baseToken":{"address":"NoFdv"},"priceUsd":"0.1","liquidity":{"usd":5000}}]`,
+		"/token-pairs/v1/solana/Unpriced":          `[{"pairAddress":"Pair","baseToken":{"address":"Unpriced"},"priceUsd":"","liquidity":{"usd":5000}}]`,
+		"/token-pairs/v1/solana/" + WrappedSolMint: `[{"pairAddress":"SolPair","baseToken":{"address":"` + WrappedSolMint + `"},"priceUsd":"150","liquidity":{"usd":9000000}}]`,
+	})
+	oracle := NewDexscreenerOracle(client)
+
+	price, err := oracle.TokenPrice("Mint")
+	if err != nil {
+		t.Fatalf("TokenPrice: %s", err)
+	}
+	if price.PriceUSD != 0.02 {
+		t.Errorf("PriceUSD = %v, want 0.02 from the most liquid pair", price.PriceUSD)
+	}
+	if price.Supply != 100_000_000 {
+		t.Errorf("Supply = %v, want fdv / price = 100000000", price.Supply)
+	}
+	if price.MarketCap() != 2_000_000 {
+		t.Errorf("MarketCap() = %v, want 2000000", price.MarketCap())
+	}
+
+	if price, err := oracle.TokenPrice("NoFdv"); err != nil || price.Supply != 0 {
+		t.Errorf("TokenPrice without fdv = %+v, %v, want an unknown supply", price, err)
+	}
+
+	if _, err := oracle.TokenPrice("Unpriced"); err == nil {
+		t.Error("TokenPrice without priced pairs returned no error")
+	}
+	if _, err := oracle.TokenPrice("Missing"); err == nil {
+		t.Error("TokenPrice on a 404 returned no error")
+	}
+
+	if solPrice, err := oracle.SolPrice(); err != nil || solPrice != 150 {
+		t.Errorf("SolPrice() = %v, %v, want 150", solPrice, err)
+	}
+}
+
+func TestDexscreenerOracleCachesPrices(t *testing.T) {
+	client, requests := newFakeDexscreener(t, map[string]string{
+		"/token-pairs/v1/solana/Mint": `[{"pairAddress":"Pair","baseToken":{"address":"Mint"},"priceUsd":"0.02","liquidity":{"usd":5000}}]`,
+	})
+
+	now := time.Unix(1_700_000_000, 0)
+	oracle := NewDexscreenerOracle(client)
+	oracle.SetClock(func() time.Time {
+		return now
+	})
+
+	for i := 0; i < 3; i++ {
+		if _, err := oracle.TokenPrice("Mint"); err != nil {
+			t.Fatalf("TokenPrice: %s", err)
+		}
+		now = now.Add(priceCacheTTL / 3)
+	}
+	if requests.Load() != 1 {
+		t.Errorf("made %d requests within the cache TTL, want 1", requests.Load())
+	}
+
+	if _, err := oracle.TokenPrice("Mint"); err != nil {
+		t.Fatalf("TokenPrice: %s", err)
+	}
+	if requests.Load() != 2 {
+		t.Errorf("made %d requests after the cache TTL, want 2", requests.Load())
+	}
+
+	if _, err := oracle.TokenPrice("Missing"); err == nil {
+		t.Fatal("TokenPrice on a 404 returned no error")
+	}
+	if _, err := oracle.TokenPrice("Missing"); err == nil {
+		t.Fatal("TokenPrice on a 404 returned no error")
+	}
+	if requests.Load() != 4 {
+		t.Errorf("made %d requests, want failed lookups not to be cached", requests.Load())
+	}
+}
@@ -0,0 +1,138 @@
+package buybot
+
+import "consul-telegram-bot/internal/utils"
+
+const (
+	USDCMint       = "EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v"
+	USDTMint       = "Es9vMFrzaCERmJfrF4H2FYD4KCoNkY9wWATvDUbWEJrs"
+	minStableSpend = 1.0
+)
+
+type stableQuote struct {
+	Mint   string
+	Symbol string
+}
+
+var stableQuotes = []stableQuote{
+	{Mint: USDCMint, Symbol: "USDC"},
+	{Mint: USDTMint, Symbol: "USDT"},
+}
+
+type quoteSpend struct {
+	Symbol    string
+	Amount    float64
+	SolAmount float64
+	Stable    bool
+}
+
+func isStableSymbol(symbol string) bool {
+	for _, quote := range stableQuotes {
+		if quote.Symbol == symbol {
+			return true
+		}
+	}
+	return false
+}
+
+func buyerQuoteSpend(tx *TransactionResponse, buyer string) *quoteSpend {
+	for _, quote := range stableQuotes {
+		if spent := ownerTokenSpend(tx.Meta, quote.Mint, buyer); spent > 0 {
+			return &quoteSpend{
+				Symbol: quote.Symbol,
+				Amount: spent,
+				Stable: true,
+			}
+		}
+	}
+
+	nativeSpent := float64(ownerLamportSpend(tx, buyer)) / 1e9
+	wrappedSpent := ownerTokenSpend(tx.Meta, WrappedSolMint, buyer)
+
+	return solQuote(nativeSpent, wrappedSpent)
+}
+
+func sellerQuoteProceeds(tx *TransactionResponse, seller string) *quoteSpend {
+	for _, quote := range stableQuotes {
+		if received := -ownerTokenSpend(tx.Meta, quote.Mint, seller); received > 0 {
+			return &quoteSpend{
+				Symbol: quote.Symbol,
+				Amount: received,
+				Stable: true,
+			}
+		}
+	}
+
+	nativeReceived := -float64(ownerLamportSpend(tx, seller)) / 1e9
+	wrappedReceived := -ownerTokenSpend(tx.Meta, WrappedSolMint, seller)
+
+	return solQuote(nativeReceived, wrappedReceived)
+}
+
+func solQuote(native float64, wrapped float64) *quoteSpend {
+	symbol := "SOL"
+	if native <= 0 && wrapped > 0 {
+		symbol = "WSOL"
+	}
+
+	return &quoteSpend{
+		Symbol:    symbol,
+		Amount:    native + wrapped,
+		SolAmount: native + wrapped,
+	}
+}
+
+func ownerTokenSpend(meta *TransactionMeta, mint string, owner string) float64 {
+	for _, balance := range tokenBalancesByOwner(meta, mint) {
+		if balance.Owner == owner {
+			return -utils.TokenAmountToFloat(balance.Delta(), balance.Decimals)
+		}
+	}
+	return 0
+}
+
+func ownerLamportSpend(tx *TransactionResponse, owner string) int64 {
+	for i, key := range tx.AccountKeys() {
+		if key != owner || i >= len(tx.Meta.PreBalances) || i >= len(tx.Meta.PostBalances) {
+			continue
+		}
+
+		spent := int64(tx.Meta.PreBalances[i]) - int64(tx.Meta.PostBalances[i])
+		if i == 0 {
+			spent -= int64(tx.Meta.Fee)
+		}
+		return spent
+	}
+	return 0
+}
+
+func (b *BuyTransaction) PaidInStable() bool {
+	return isStableSymbol(b.QuoteSymbol)
+}
+
+func (b *BuyTransaction) USDValue(solPrice float64) float64 {
+	if b.PaidInStable() {
+		return b.QuoteAmount
+	}
+	return b.SolAmount * solPrice
+}
+
+func (b *BuyTransaction) FormatSpent() string {
+	if b.QuoteSymbol == "" || b.QuoteSymbol == "SOL" {
+		return utils.FormatNumber(b.SolAmount, "SOL")
+	}
+	return utils.FormatNumber(b.QuoteAmount, b.QuoteSymbol)
+}
+
+func (s *SellTransaction) USDValue(solPrice float64) float64 {
+	if isStableSymbol(s.QuoteSymbol) {
+		return s.QuoteAmount
+	}
+	return s.SolAmount * solPrice
+}
+
+func (s *SellTransaction) FormatReceived() string {
+	if s.QuoteSymbol == "" || s.QuoteSymbol == "SOL" {
+		return utils.FormatNumber(s.SolAmount, "SOL")
+	}
+	return utils.FormatNumber(s.QuoteAmount, s.QuoteSymbol)
+}
@@ -0,0 +1,139 @@
+package buybot
+
+import (
+	"consul-telegram-bot/internal/config"
+	"consul-telegram-bot/internal/logger"
+	"consul-telegram-bot/internal/model"
+	"sync"
+)
+
+const (
+	ModePolling = "polling"
+	ModeWebhook = "webhook"
+)
+
+type Registry struct {
+	client       *HeliusClient
+	streamClient *StreamClient
+	logger       *logger.Logger
+	config       *config.Config
+	signalSender *SignalSender
+	snipers      *SniperDetector
+	monitors     map[string]*Monitor
+	mu           sync.Mutex
+}
+
+func NewRegistry(client *HeliusClient, logger *logger.Logger, cfg *config.Config, signalSender *SignalSender) *Registry {
+	snipers := NewSniperDetector(client, logger, NewSniperConfig(cfg))
+	snipers.SetAlertHandler(signalSender.SendSniperAlert)
+
+	return &Registry{
+		client:       client,
+		logger:       logger,
+		config:       cfg,
+		signalSender: signalSender,
+		snipers:      snipers,
+		monitors:     make(map[string]*Monitor),
+	}
+}
+
+func (r *Registry) SetStreamClient(streamClient *StreamClient) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.streamClient = streamClient
+}
+
+func (r *Registry) Sync() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tokenAddresses := r.collectTokenAddresses()
+
+	for tokenAddress, monitor := range r.monitors {
+		if tokenAddresses[tokenAddress] {
+			continue
+		}
+
+		r.logger.Info("token %s is no longer configured, stopping buy monitor", tokenAddress)
+		monitor.Stop()
+		delete(r.monitors, tokenAddress)
+	}
+
+	for tokenAddress := range tokenAddresses {
+		if _, exists := r.monitors[tokenAddress]; exists {
+			continue
+		}
+
+		monitor := NewMonitor(r.client, r.logger, tokenAddress, r.config.BuyBotBackfillLimit)
+		monitor.SetBuyBatchHandler(func(buyTxs []*BuyTransaction) {
+			r.recordBuys(buyTxs)
+			r.signalSender.SendBuySignals(buyTxs)
+			r.signalSender.CollectDigestBuys(buyTxs)
+			if r.signalSender.hasSniperRecipients(buyTxs[0].Mint) {
+				go r.snipers.Observe(buyTxs)
+			}
+		})
+		monitor.SetSellHandler(func(sellTx *SellTransaction) {
+			r.signalSender.SendSellSignal(sellTx)
+		})
+
+		if r.signalSender.priceOracle != nil {
+			monitor.SetPriceOracle(r.signalSender.priceOracle)
+		}
+
+		if r.streamClient != nil {
+			monitor.SetStreamClient(r.streamClient)
+		}
+
+		r.monitors[tokenAddress] = monitor
+
+		if r.config.BuyBotMode == ModeWebhook {
+			monitor.restoreCursor()
+			r.logger.Info("receiving buys for token %s via webhook", tokenAddress)
+			continue
+		}
+
+		go monitor.Start()
+	}
+}
+
+func (r *Registry) HandleWebhookTransactions(transactions []EnhancedTransaction) error {
+	r.mu.Lock()
+	monitors := make([]*Monitor, 0, len(r.monitors))
+	for _, monitor := range r.monitors {
+		monitors = append(monitors, monitor)
+	}
+	r.mu.Unlock()
+
+	var firstErr error
+	for _, monitor := range monitors {
+		if err := monitor.handleWebhookTransactions(transactions); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
+
+func (r *Registry) collectTokenAddresses() map[string]bool {
+	tokenAddresses := make(map[string]bool)
+
+	model.IterateRecipients(func(recipient *model.Recipient) {
+		if recipient.Receiving != 1 {
+			return
+		}
+
+		if recipient.GetThreadIdForSignalType(model.SignalTypeBuys) == 0 &&
+			recipient.GetThreadIdForSignalType(model.SignalTypeSells) == 0 &&
+			recipient.GetThreadIdForSignalType(model.SignalTypeSnipers) == 0 {
+			return
+		}
+
+		tokenAddress := model.GetWithFallback(recipient.TokenAddress, r.config.TokenAddress)
+		if tokenAddress != "" {
+			tokenAddresses[tokenAddress] = true
+		}
+	})
+
+	return tokenAddresses
+}
@@ -0,0 +1,137 @@
+package buybot
+
+import (
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	rpcMaxAttempts       = 4
+	rpcMinBackoff        = 250 * time.Millisecond
+	rpcMaxBackoff        = 10 * time.Second
+	rpcMaxRetryAfter     = 30 * time.Second
+	rpcFailureThreshold  = 3
+	rpcUnhealthyCooldown = 30 * time.Second
+	rpcCodeRateLimited   = 429
+)
+
+type rpcCallError struct {
+	err       error
+	retryable bool
+}
+
+func (e *rpcCallError) Error() string {
+	return e.err.Error()
+}
+
+func (e *rpcCallError) Unwrap() error {
+	return e.err
+}
+
+type rpcEndpoint struct {
+	url            string
+	label          string
+	das            bool
+	failures       int
+	unhealthyUntil time.Time
+	mu             sync.Mutex
+}
+
+func newRPCEndpoint(rpcURL string) *rpcEndpoint {
+	label := rpcURL
+	if parsed, err := url.Parse(rpcURL); err == nil && parsed.Host != "" {
+		label = parsed.Host
+	}
+
+	return &rpcEndpoint{
+		url:   rpcURL,
+		label: label,
+		das:   isHeliusHost(label),
+	}
+}
+
+// isHeliusHost reports whether the endpoint serves Helius' DAS methods such
+// as getTokenAccounts, which plain Solana RPC providers reject.
+func isHeliusHost(host string) bool {
+	return strings.HasSuffix(host, "helius-rpc.com") || strings.HasSuffix(host, "helius.xyz")
+}
+
+func (e *rpcEndpoint) healthy() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return time.Now().After(e.unhealthyUntil)
+}
+
+func (e *rpcEndpoint) recoversAt() time.Time {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.unhealthyUntil
+}
+
+func (e *rpcEndpoint) markSuccess() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.failures = 0
+	e.unhealthyUntil = time.Time{}
+}
+
+func (e *rpcEndpoint) markFailure() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.failures++
+	if e.failures >= rpcFailureThreshold {
+		e.unhealthyUntil = time.Now().Add(rpcUnhealthyCooldown)
+	}
+}
+
+func pickEndpoint(endpoints []*rpcEndpoint) *rpcEndpoint {
+	for _, endpoint := range endpoints {
+		if endpoint.healthy() {
+			return endpoint
+		}
+	}
+
+	soonest := endpoints[0]
+	for _, endpoint := range endpoints[1:] {
+		if endpoint.recoversAt().Before(soonest.recoversAt()) {
+			soonest = endpoint
+		}
+	}
+
+	return soonest
+}
+
+func rpcBackoff(attempt int) time.Duration {
+	backoff := rpcMinBackoff << attempt
+	if backoff > rpcMaxBackoff {
+		backoff = rpcMaxBackoff
+	}
+	return backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+}
+
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	var delay time.Duration
+	if seconds, err := strconv.Atoi(value); err == nil {
+		delay = time.Duration(seconds) * time.Second
+	} else if at, err := http.ParseTime(value); err == nil {
+		delay = time.Until(at)
+	}
+
+	if delay < 0 {
+		return 0
+	}
+	if delay > rpcMaxRetryAfter {
+		return rpcMaxRetryAfter
+	}
+	return delay
+}
@@ -0,0 +1,90 @@
+package buybot
+
+import (
+	"math/big"
+)
+
+type SellTransaction struct {
+	Mint         string
+	Signature    string
+	Seller       string
+	Amount       *big.Int
+	Decimals     int
+	SolAmount    float64
+	QuoteSymbol  string
+	QuoteAmount  float64
+	PositionSold float64
+	BlockTime    int64
+}
+
+func (m *Monitor) SetSellHandler(handler func(*SellTransaction)) {
+	m.onSellTransaction = handler
+}
+
+func (m *Monitor) dispatchSells(sellTransactions []*SellTransaction) {
+	if m.onSellTransaction == nil {
+		return
+	}
+
+	for _, sellTx := range sellTransactions {
+		m.logger.Info("sending sell: %s tokens for %.4f SOL", sellTx.Amount, sellTx.SolAmount)
+		m.onSellTransaction(sellTx)
+	}
+}
+
+func (m *Monitor) analyzeSellTransaction(tx *TransactionResponse, signature string) *SellTransaction {
+	if tx.Meta == nil || tx.Meta.Err != nil {
+		return nil
+	}
+
+	var sellerBalance *ownerTokenBalance
+	for _, balance := range tokenBalancesByOwner(tx.Meta, m.tokenAddress) {
+		if balance.Owner == "" || balance.Delta().Sign() >= 0 || !tx.IsSigner(balance.Owner) {
+			continue
+		}
+
+		if sellerBalance == nil || balance.Delta().Cmp(sellerBalance.Delta()) < 0 {
+			sellerBalance = balance
+		}
+	}
+
+	if sellerBalance == nil {
+		return nil
+	}
+
+	tokenAmount := new(big.Int).Neg(sellerBalance.Delta())
+
+	quote := m.sellProceeds(sellerQuoteProceeds(tx, sellerBalance.Owner))
+	if quote == nil {
+		return nil
+	}
+
+	blockTime := int64(0)
+	if tx.BlockTime != nil {
+		blockTime = *tx.BlockTime
+	}
+
+	return &SellTransaction{
+		Mint:         m.tokenAddress,
+		Signature:    signature,
+		Seller:       sellerBalance.Owner,
+		Amount:       tokenAmount,
+		Decimals:     sellerBalance.Decimals,
+		SolAmount:    quote.SolAmount,
+		QuoteSymbol:  quote.Symbol,
+		QuoteAmount:  quote.Amount,
+		PositionSold: percentOf(tokenAmount, sellerBalance.Pre),
+		BlockTime:    blockTime,
+	}
+}
+
+func (m *Monitor) sellProceeds(quote *quoteSpend) *quoteSpend {
+	if quote.Stable {
+		if quote.Amount < minStableSpend {
+			return nil
+		}
+		quote.SolAmount = m.stableToSol(quote.Amount)
+	}
+
+	return quote
+}
@@ -0,0 +1,145 @@
+package buybot
+
+import (
+	"consul-telegram-bot/internal/logger"
+	"testing"
+)
+
+func tokenBalance(index int, owner string, mint string, amount string, decimals int) TokenBalance {
+	return TokenBalance{
+		AccountIndex:  index,
+		Mint:          mint,
+		Owner:         owner,
+		UiTokenAmount: TokenAmount{Amount: amount, Decimals: decimals},
+	}
+}
+
+func TestAnalyzeSellTransactionProceeds(t *testing.T) {
+	tests := []struct {
+		name        string
+		tx          *TransactionResponse
+		wantSeller  string
+		wantSymbol  string
+		wantAmount  float64
+		wantNoSells bool
+	}{
+		{
+			name: "seller pays the fee and receives SOL",
+			tx: &TransactionResponse{
+				Transaction: TransactionData{Message: MessageData{
+					Header:      MessageHeader{NumRequiredSignatures: 1},
+					AccountKeys: []string{"Seller", "Pool"},
+				}},
+				Meta: &TransactionMeta{
+					Fee:               5000,
+					PreBalances:       []uint64{1_000_000_000, 10_000_000_000},
+					PostBalances:      []uint64{2_999_995_000, 8_000_000_000},
+					PreTokenBalances:  []TokenBalance{tokenBalance(2, "Seller", "Mint", "5000000", 6), tokenBalance(3, "Pool", "Mint", "0", 6)},
+					PostTokenBalances: []TokenBalance{tokenBalance(2, "Seller", "Mint", "0", 6), tokenBalance(3, "Pool", "Mint", "5000000", 6)},
+				},
+			},
+			wantSeller: "Seller",
+			wantSymbol: "SOL",
+			wantAmount: 2,
+		},
+		{
+			name: "relayer pays the fee",
+			tx: &TransactionResponse{
+				Transaction: TransactionData{Message: MessageData{
+					Header:      MessageHeader{NumRequiredSignatures: 2},
+					AccountKeys: []string{"Relayer", "Seller", "Pool"},
+				}},
+				Meta: &TransactionMeta{
+					Fee:               5000,
+					PreBalances:       []uint64{1_000_000_000, 1_000_000_000, 10_000_000_000},
+					PostBalances:      []uint64{999_995_000, 2_500_000_000, 8_500_000_000},
+					PreTokenBalances:  []TokenBalance{tokenBalance(3, "Seller", "Mint", "5000000", 6)},
+					PostTokenBalances: []TokenBalance{tokenBalance(3, "Seller", "Mint", "1000000", 6)},
+				},
+			},
+			wantSeller: "Seller",
+			wantSymbol: "SOL",
+			wantAmount: 1.5,
+		},
+		{
+			name: "proceeds paid in WSOL",
+			tx: &TransactionResponse{
+				Transaction: TransactionData{Message: MessageData{
+					Header:      MessageHeader{NumRequiredSignatures: 1},
+					AccountKeys: []string{"Seller"},
+				}},
+				Meta: &TransactionMeta{
+					Fee:               5000,
+					PreBalances:       []uint64{1_000_000_000},
+					PostBalances:      []uint64{999_995_000},
+					PreTokenBalances:  []TokenBalance{tokenBalance(1, "Seller", "Mint", "5000000", 6), tokenBalance(2, "Seller", WrappedSolMint, "0", 9)},
+					PostTokenBalances: []TokenBalance{tokenBalance(1, "Seller", "Mint", "0", 6), tokenBalance(2, "Seller", WrappedSolMint, "3000000000", 9)},
+				},
+			},
+			wantSeller: "Seller",
+			wantSymbol: "WSOL",
+			wantAmount: 3,
+		},
+		{
+			name: "proceeds paid in USDC",
+			tx: &TransactionResponse{
+				Transaction: TransactionData{Message: MessageData{
+					Header:      MessageHeader{NumRequiredSignatures: 1},
+					AccountKeys: []string{"Seller"},
+				}},
+				Meta: &TransactionMeta{
+					Fee:               5000,
+					PreBalances:       []uint64{1_000_000_000},
+					PostBalances:      []uint64{999_995_000},
+					PreTokenBalances:  []TokenBalance{tokenBalance(1, "Seller", "Mint", "5000000", 6), tokenBalance(2, "Seller", USDCMint, "0", 6)},
+					PostTokenBalances: []TokenBalance{tokenBalance(1, "Seller", "Mint", "0", 6), tokenBalance(2, "Seller", USDCMint, "250000000", 6)},
+				},
+			},
+			wantSeller: "Seller",
+			wantSymbol: "USDC",
+			wantAmount: 250,
+		},
+		{
+			name: "pool sends tokens on a buy",
+			tx: &TransactionResponse{
+				Transaction: TransactionData{Message: MessageData{
+					Header:      MessageHeader{NumRequiredSignatures: 1},
+					AccountKeys: []string{"Buyer", "Pool"},
+				}},
+				Meta: &TransactionMeta{
+					Fee:               5000,
+					PreBalances:       []uint64{3_000_000_000, 10_000_000_000},
+					PostBalances:      []uint64{999_995_000, 12_000_000_000},
+					PreTokenBalances:  []TokenBalance{tokenBalance(2, "Pool", "Mint", "5000000", 6)},
+					PostTokenBalances: []TokenBalance{tokenBalance(2, "Pool", "Mint", "0", 6), tokenBalance(3, "Buyer", "Mint", "5000000", 6)},
+				},
+			},
+			wantNoSells: true,
+		},
+	}
+
+	monitor := NewMonitor(nil, logger.New(), "Mint", 100)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sellTx := monitor.analyzeSellTransaction(tt.tx, "sig")
+
+			if tt.wantNoSells {
+				if sellTx != nil {
+					t.Fatalf("got sell by %s, want none", sellTx.Seller)
+				}
+				return
+			}
+
+			if sellTx == nil {
+				t.Fatal("got no sell")
+			}
+			if sellTx.Seller != tt.wantSeller {
+				t.Errorf("Seller = %s, want %s", sellTx.Seller, tt.wantSeller)
+			}
+			if sellTx.QuoteSymbol != tt.wantSymbol || sellTx.QuoteAmount != tt.wantAmount {
+				t.Errorf("proceeds = %v %s, want %v %s", sellTx.QuoteAmount, sellTx.QuoteSymbol, tt.wantAmount, tt.wantSymbol)
+			}
+		})
+	}
+}
@@ -8,19 +8,48 @@ import (
 	"consul-telegram-bot/internal/utils"
 
 	"fmt"
+	"math/big"
 	"math/rand"
 	"path/filepath"
+	"strings"
+	"sync"
 	"time"
 
 	telebot "gopkg.in/telebot.v3"
 )
 
+const (
+	defaultMinBuySol  = 0.01
+	defaultMinSellSol = 1.0
+	buyThrottleWindow = time.Minute
+)
+
 type SignalSender struct {
-	bot      *bot.Bot
-	logger   *logger.Logger
-	config   *config.Config
-	gifPaths []string
-	rng      *rand.Rand
+	bot         *bot.Bot
+	logger      *logger.Logger
+	config      *config.Config
+	priceOracle PriceOracle
+	gifPaths    []string
+	rng         *rand.Rand
+	rngMu       sync.Mutex
+	digests     map[int64]*buyDigest
+	digestMu    sync.Mutex
+	gifFileIDs  map[string]string
+	gifFileMu   sync.RWMutex
+	throttles   map[int64]*buyThrottle
+	throttleMu  sync.Mutex
+	now         func() time.Time
+}
+
+type buyThrottle struct {
+	sentAt time.Time
+	amount *big.Int
+}
+
+type buyValuation struct {
+	USD       float64
+	PriceUSD  float64
+	MarketCap float64
 }
 
 func NewSignalSender(botInstance *bot.Bot, logger *logger.Logger, cfg *config.Config) *SignalSender {
@@ -34,16 +63,32 @@ func NewSignalSender(botInstance *bot.Bot, logger *logger.Logger, cfg *config.Co
 	}
 
 	return &SignalSender{
-		bot:      botInstance,
-		logger:   logger,
-		config:   cfg,
-		gifPaths: gifPaths,
-		rng:      rand.New(rand.NewSource(time.Now().UnixNano())),
+		bot:        botInstance,
+		logger:     logger,
+		config:     cfg,
+		gifPaths:   gifPaths,
+		rng:        rand.New(rand.NewSource(time.Now().UnixNano())),
+		digests:    make(map[int64]*buyDigest),
+		gifFileIDs: make(map[string]string),
+		throttles:  make(map[int64]*buyThrottle),
+		now:        time.Now,
 	}
 }
 
-func (s *SignalSender) SendBuySignal(buyTx *BuyTransaction) {
-	s.logger.Info("sending buy signal for tx: %s", buyTx.Signature)
+func (s *SignalSender) SetPriceOracle(priceOracle PriceOracle) {
+	s.priceOracle = priceOracle
+}
+
+func (s *SignalSender) SetClock(now func() time.Time) {
+	s.now = now
+}
+
+// SendBuySignals filters the batch for every chat on its own and sends the
+// largest buy that chat accepts, unless it is throttled.
+func (s *SignalSender) SendBuySignals(buyTxs []*BuyTransaction) {
+	if len(buyTxs) == 0 {
+		return
+	}
 
 	recipients, err := s.getAllRecipients()
 	if err != nil {
@@ -51,49 +96,336 @@ func (s *SignalSender) SendBuySignal(buyTx *BuyTransaction) {
 		return
 	}
 
-	gifPath := s.getRandomGif()
+	valuations := s.valueBuys(buyTxs)
 
 	for _, recipient := range recipients {
 		threadId := recipient.GetThreadIdForSignalType(model.SignalTypeBuys)
+		if threadId == 0 {
+			continue
+		}
+
+		if model.GetWithFallback(recipient.TokenAddress, s.config.TokenAddress) != buyTxs[0].Mint {
+			continue
+		}
+
+		if recipient.BuyDigestMinutes > 0 {
+			continue
+		}
+
+		buyTx, skipped := s.selectBuy(recipient, buyTxs, valuations)
+		for signature, reason := range skipped {
+			s.logger.Info("skipping buy %s for chat %d: %s", signature, recipient.Id, reason)
+		}
+
+		if buyTx == nil {
+			continue
+		}
+
+		valuation := valuations[buyTx.Signature]
+		ticker := model.GetWithFallback(recipient.TokenTicker, s.config.TokenTicker)
+		tier := model.FindBuyTier(recipient.GetBuyTiers(), buyTx.SolAmount)
+
+		message := s.renderBuyMessage(recipient, buyTx, ticker, tier, valuation)
+		keyboard := s.buyKeyboard(recipient, buyTemplateValues(buyTx, ticker, tier, valuation, recipient))
+
+		s.logger.Info("sending buy signal for tx %s to chat %d, thread %d", buyTx.Signature, recipient.Id, threadId)
+		s.sendAnimationWithCaption(recipient, tier, message, threadId, keyboard)
+	}
+}
+
+// SelectBuySignal returns the buy of the batch that would be sent to the
+// recipient, along with the reason every other buy was skipped.
+func (s *SignalSender) SelectBuySignal(recipient *model.Recipient, buyTxs []*BuyTransaction) (*BuyTransaction, map[string]string) {
+	return s.selectBuy(recipient, buyTxs, s.valueBuys(buyTxs))
+}
+
+func (s *SignalSender) selectBuy(recipient *model.Recipient, buyTxs []*BuyTransaction, valuations map[string]*buyValuation) (*BuyTransaction, map[string]string) {
+	skipped := make(map[string]string)
+
+	var largest *BuyTransaction
+	for _, buyTx := range buyTxs {
+		if reason := s.buySkipReason(recipient, buyTx, valuations[buyTx.Signature]); reason != "" {
+			skipped[buyTx.Signature] = reason
+			continue
+		}
+
+		if largest == nil {
+			largest = buyTx
+			continue
+		}
+
+		if buyTx.Amount.Cmp(largest.Amount) > 0 {
+			largest, buyTx = buyTx, largest
+		}
+		skipped[buyTx.Signature] = "smaller than the batch's largest buy"
+	}
+
+	if largest == nil {
+		return nil, skipped
+	}
+
+	if !s.takeThrottle(recipient.Id, largest) {
+		skipped[largest.Signature] = "throttled"
+		return nil, skipped
+	}
+
+	return largest, skipped
+}
+
+func (s *SignalSender) buySkipReason(recipient *model.Recipient, buyTx *BuyTransaction, valuation *buyValuation) string {
+	if !s.meetsMinimumBuy(recipient, buyTx, valuation) {
+		return "below minimum buy"
+	}
+
+	if recipient.NewHoldersOnly && !buyTx.NewHolder {
+		return "not a new holder"
+	}
+
+	if !recipient.AcceptsVenue(buyTx.VenueKeys()...) {
+		return fmt.Sprintf("venue %s is filtered out", buyTx.Venue)
+	}
+
+	if model.IsWalletIgnored(recipient.Id, buyTx.Buyer) {
+		return fmt.Sprintf("buyer %s is ignored", buyTx.Buyer)
+	}
+
+	return ""
+}
+
+// takeThrottle reports whether a buy may be sent to the chat: within
+// buyThrottleWindow of the last signal only a larger buy goes through.
+func (s *SignalSender) takeThrottle(chatID int64, buyTx *BuyTransaction) bool {
+	s.throttleMu.Lock()
+	defer s.throttleMu.Unlock()
+
+	now := s.now()
+	if last, ok := s.throttles[chatID]; ok && now.Sub(last.sentAt) < buyThrottleWindow && buyTx.Amount.Cmp(last.amount) <= 0 {
+		return false
+	}
+
+	s.throttles[chatID] = &buyThrottle{sentAt: now, amount: buyTx.Amount}
+	return true
+}
+
+func (s *SignalSender) valueBuys(buyTxs []*BuyTransaction) map[string]*buyValuation {
+	valuations := make(map[string]*buyValuation, len(buyTxs))
+	for _, buyTx := range buyTxs {
+		valuations[buyTx.Signature] = s.valueBuy(buyTx)
+	}
+	return valuations
+}
+
+func (s *SignalSender) PreviewBuySignal(buyTx *BuyTransaction, recipient *model.Recipient) string {
+	ticker := model.GetWithFallback(recipient.TokenTicker, s.config.TokenTicker)
+	tier := model.FindBuyTier(recipient.GetBuyTiers(), buyTx.SolAmount)
+
+	return s.renderBuyMessage(recipient, buyTx, ticker, tier, s.valueBuy(buyTx))
+}
+
+func (s *SignalSender) PreviewSellSignal(sellTx *SellTransaction, recipient *model.Recipient) string {
+	var usdValue float64
+	if s.priceOracle != nil {
+		if solPrice, err := s.priceOracle.SolPrice(); err == nil {
+			usdValue = sellTx.USDValue(solPrice)
+		}
+	}
+
+	ticker := model.GetWithFallback(recipient.TokenTicker, s.config.TokenTicker)
+
+	return s.formatSellMessage(sellTx, ticker, usdValue, recipient)
+}
+
+func (s *SignalSender) SendSellSignal(sellTx *SellTransaction) {
+	s.logger.Info("sending sell signal for tx: %s (token %s)", sellTx.Signature, sellTx.Mint)
+
+	recipients, err := s.getAllRecipients()
+	if err != nil {
+		s.logger.Error("failed to get recipients: %s", err)
+		return
+	}
+
+	var usdValue float64
+	if s.priceOracle != nil {
+		if solPrice, err := s.priceOracle.SolPrice(); err == nil {
+			usdValue = sellTx.USDValue(solPrice)
+		}
+	}
+
+	for _, recipient := range recipients {
+		threadId := recipient.GetThreadIdForSignalType(model.SignalTypeSells)
 
 		if threadId == 0 {
 			continue
 		}
 
+		if model.GetWithFallback(recipient.TokenAddress, s.config.TokenAddress) != sellTx.Mint {
+			continue
+		}
+
+		minSellSol := recipient.MinSellSol
+		if minSellSol == 0 {
+			minSellSol = defaultMinSellSol
+		}
+
+		if sellTx.SolAmount < minSellSol {
+			continue
+		}
+
+		if model.IsWalletIgnored(recipient.Id, sellTx.Seller) {
+			continue
+		}
+
 		ticker := model.GetWithFallback(recipient.TokenTicker, s.config.TokenTicker)
 		dexURL := model.GetWithFallback(recipient.DexURL, s.config.DexURL)
-		axiomURL := model.GetWithFallback(recipient.AxiomURL, s.config.AxiomURL)
 
-		message := s.formatBuyMessage(buyTx, ticker)
+		message := s.formatSellMessage(sellTx, ticker, usdValue, recipient)
+
+		s.logger.Info("sending sell signal to chat %d, thread %d", recipient.Id, threadId)
+		s.sendSellMessage(recipient, message, threadId, sellTx, dexURL)
+	}
+}
+
+func (s *SignalSender) meetsMinimumBuy(recipient *model.Recipient, buyTx *BuyTransaction, valuation *buyValuation) bool {
+	minBuySol := recipient.MinBuySol
+	if minBuySol == 0 && recipient.MinBuyUSD == 0 && !buyTx.PaidInStable() {
+		minBuySol = defaultMinBuySol
+	}
+
+	if minBuySol > 0 && buyTx.SolAmount < minBuySol {
+		return false
+	}
+
+	if recipient.MinBuyUSD > 0 {
+		if valuation == nil || valuation.USD == 0 {
+			s.logger.Warning("USD value unknown for tx %s, ignoring minimum buy of chat %d", buyTx.Signature, recipient.Id)
+			return true
+		}
+
+		if valuation.USD < recipient.MinBuyUSD {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (s *SignalSender) valueBuy(buyTx *BuyTransaction) *buyValuation {
+	valuation := &buyValuation{}
+	if buyTx.PaidInStable() {
+		valuation.USD = buyTx.QuoteAmount
+	}
+
+	if s.priceOracle == nil {
+		if valuation.USD == 0 {
+			return nil
+		}
+		return valuation
+	}
+
+	if valuation.USD == 0 {
+		solPrice, err := s.priceOracle.SolPrice()
+		if err != nil {
+			s.logger.Warning("failed to get SOL price: %s", err)
+		} else {
+			valuation.USD = buyTx.SolAmount * solPrice
+		}
+	}
+
+	tokenPrice, err := s.priceOracle.TokenPrice(buyTx.Mint)
+	if err != nil {
+		s.logger.Warning("failed to get token price for %s: %s", buyTx.Mint, err)
+	} else {
+		valuation.PriceUSD = tokenPrice.PriceUSD
+		valuation.MarketCap = tokenPrice.MarketCap()
+	}
+
+	return valuation
+}
+
+func (s *SignalSender) formatBuyMessage(buyTx *BuyTransaction, ticker string, tier *model.BuyTier, valuation *buyValuation, recipient *model.Recipient) string {
+	if ticker == "" {
+		ticker = "TOKEN"
+	}
+
+	explorer := recipient.GetExplorer()
+
+	emoji := model.DefaultBuyTiers[0].Emoji
+	if tier != nil && tier.Emoji != "" {
+		emoji = utils.EscapeHTML(tier.Emoji)
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("<b>$%s BUY %s</b>\n", ticker, emoji))
+	if buyTx.Venue != nil {
+		venue := buyTx.Venue.Name
+		if buyTx.Router != nil {
+			venue += " (" + buyTx.Router.Name + ")"
+		}
+		sb.WriteString(fmt.Sprintf("<i>via %s</i>\n", venue))
+	}
+	sb.WriteString("\n")
+	sb.WriteString(fmt.Sprintf("<b>💰 Amount:</b> %s\n", utils.FormatTokenAmount(buyTx.Amount, buyTx.Decimals, ticker)))
+
+	spent := buyTx.FormatSpent()
+	if valuation != nil && valuation.USD > 0 {
+		spent += " (" + utils.FormatUSD(valuation.USD) + ")"
+	}
+	sb.WriteString(fmt.Sprintf("<b>💵 Spent:</b> %s\n", spent))
+
+	if valuation != nil && valuation.PriceUSD > 0 {
+		sb.WriteString(fmt.Sprintf("<b>🏷 Price:</b> %s\n", utils.FormatPrice(valuation.PriceUSD)))
+	}
+
+	if valuation != nil && valuation.MarketCap > 0 {
+		sb.WriteString(fmt.Sprintf("<b>📊 Market Cap:</b> %s\n", utils.FormatUSD(valuation.MarketCap)))
+	}
 
-		s.logger.Info("sending buy signal to chat %d, thread %d", recipient.Id, threadId)
-		s.sendAnimationWithCaption(recipient, gifPath, message, threadId, dexURL, axiomURL)
+	if buyTx.NewHolder {
+		sb.WriteString("<b>🆕 New holder</b>\n")
+	} else if buyTx.PositionIncrease > 0 {
+		sb.WriteString(fmt.Sprintf("<b>📈 Position:</b> +%s\n", utils.FormatPercentage(buyTx.PositionIncrease, 2)))
 	}
+
+	sb.WriteString(fmt.Sprintf("<b>🦊 Buyer:</b> <a href=\"%s\">%s</a>\n", explorer.AccountURL(buyTx.Buyer), utils.EscapeHTML(walletName(recipient, buyTx.Buyer))))
+	sb.WriteString(fmt.Sprintf("<b>🔎 Transaction:</b> <a href=\"%s\">%s</a>", explorer.TxURL(buyTx.Signature), utils.ShortenAddress(buyTx.Signature)))
+
+	return sb.String()
 }
 
-func (s *SignalSender) formatBuyMessage(buyTx *BuyTransaction, ticker string) string {
+func (s *SignalSender) formatSellMessage(sellTx *SellTransaction, ticker string, usdValue float64, recipient *model.Recipient) string {
 	if ticker == "" {
 		ticker = "TOKEN"
 	}
 
-	return fmt.Sprintf(
-		"<b>$%s BUY 🥬🥦🌿🌵🌳☘️</b>\n\n"+
-			"<b>💰 Amount:</b> %s\n"+
-			"<b>🦊 Buyer:</b> %s\n"+
-			"<b>🔎 Transaction:</b> <a href=\"%s\">%s</a>",
-		ticker,
-		utils.FormatNumber(buyTx.Amount, ticker),
-		s.shortenAddress(buyTx.Buyer),
-		buyTx.TxURL,
-		s.shortenAddress(buyTx.Signature),
-	)
+	explorer := recipient.GetExplorer()
+
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("<b>$%s SELL 🔻🔻🔻</b>\n\n", ticker))
+	sb.WriteString(fmt.Sprintf("<b>💰 Amount:</b> %s\n", utils.FormatTokenAmount(sellTx.Amount, sellTx.Decimals, ticker)))
+
+	received := sellTx.FormatReceived()
+	if usdValue > 0 && !isStableSymbol(sellTx.QuoteSymbol) {
+		received += " (" + utils.FormatUSD(usdValue) + ")"
+	}
+	sb.WriteString(fmt.Sprintf("<b>💵 Received:</b> %s\n", received))
+
+	if sellTx.PositionSold > 0 {
+		sb.WriteString(fmt.Sprintf("<b>📉 Position sold:</b> %s\n", utils.FormatPercentage(sellTx.PositionSold, 2)))
+	}
+
+	sb.WriteString(fmt.Sprintf("<b>🦊 Seller:</b> <a href=\"%s\">%s</a>\n", explorer.AccountURL(sellTx.Seller), utils.EscapeHTML(walletName(recipient, sellTx.Seller))))
+	sb.WriteString(fmt.Sprintf("<b>🔎 Transaction:</b> <a href=\"%s\">%s</a>", explorer.TxURL(sellTx.Signature), utils.ShortenAddress(sellTx.Signature)))
+
+	return sb.String()
 }
 
-func (s *SignalSender) shortenAddress(address string) string {
-	if len(address) <= 12 {
-		return address
+func walletName(recipient *model.Recipient, address string) string {
+	if label, err := model.FindWalletLabel(recipient.Id, address); err == nil && label.Label != "" {
+		return label.Label
 	}
-	return address[:6] + "..." + address[len(address)-4:]
+	return utils.ShortenAddress(address)
 }
 
 func (s *SignalSender) getAllRecipients() ([]*model.Recipient, error) {
@@ -109,46 +441,94 @@ func (s *SignalSender) getAllRecipients() ([]*model.Recipient, error) {
 	return recipients, nil
 }
 
+func (s *SignalSender) randomIndex(n int) int {
+	s.rngMu.Lock()
+	defer s.rngMu.Unlock()
+	return s.rng.Intn(n)
+}
+
 func (s *SignalSender) getRandomGif() string {
 	if len(s.gifPaths) == 0 {
 		return ""
 	}
 
-	idx := s.rng.Intn(len(s.gifPaths))
-	absPath, _ := filepath.Abs(s.gifPaths[idx])
+	absPath, _ := filepath.Abs(s.gifPaths[s.randomIndex(len(s.gifPaths))])
 	return absPath
 }
 
-func (s *SignalSender) sendAnimationWithCaption(recipient *model.Recipient, gifPath string, caption string, threadId int, dexURL string, axiomURL string) {
+func (s *SignalSender) pickAnimation(recipient *model.Recipient, tier *model.BuyTier) (telebot.File, string) {
+	if tier != nil && len(tier.Gifs) > 0 {
+		return telebot.FromURL(tier.Gifs[s.randomIndex(len(tier.Gifs))]), ""
+	}
+
+	if len(recipient.GifFileIDs) > 0 {
+		return telebot.File{FileID: recipient.GifFileIDs[s.randomIndex(len(recipient.GifFileIDs))]}, ""
+	}
+
+	gifPath := s.getRandomGif()
+
+	s.gifFileMu.RLock()
+	fileID, ok := s.gifFileIDs[gifPath]
+	s.gifFileMu.RUnlock()
+
+	if ok {
+		return telebot.File{FileID: fileID}, ""
+	}
+
+	return telebot.FromDisk(gifPath), gifPath
+}
+
+func (s *SignalSender) sendAnimationWithCaption(recipient *model.Recipient, tier *model.BuyTier, caption string, threadId int, keyboard *telebot.ReplyMarkup) {
+	file, uploadedPath := s.pickAnimation(recipient, tier)
+
 	animation := &telebot.Animation{
-		File:     telebot.FromDisk(gifPath),
+		File:     file,
 		MIME:     "image/gif",
 		Caption:  caption,
 		FileName: "animation.gif",
 	}
 
-	inlineKeyboard := &telebot.ReplyMarkup{}
+	opts := &telebot.SendOptions{
+		ParseMode:   "HTML",
+		ThreadID:    threadId,
+		ReplyMarkup: keyboard,
+	}
 
-	var buttons []telebot.Btn
-	if dexURL != "" {
-		buttons = append(buttons, inlineKeyboard.URL("Buy on Dexscreener", dexURL))
+	msg, err := s.bot.Bot.Send(recipient, animation, caption, opts)
+	if err != nil {
+		s.logger.Error("failed to send animation to chat %d: %s", recipient.Id, err)
+		return
 	}
-	if axiomURL != "" {
-		buttons = append(buttons, inlineKeyboard.URL("Buy on Axiom", axiomURL))
+
+	if uploadedPath != "" && msg != nil && msg.Animation != nil && msg.Animation.FileID != "" {
+		s.gifFileMu.Lock()
+		s.gifFileIDs[uploadedPath] = msg.Animation.FileID
+		s.gifFileMu.Unlock()
 	}
+}
 
-	if len(buttons) > 0 {
-		inlineKeyboard.Inline(inlineKeyboard.Row(buttons...))
+func (s *SignalSender) sendSellMessage(recipient *model.Recipient, text string, threadId int, sellTx *SellTransaction, dexURL string) {
+	inlineKeyboard := &telebot.ReplyMarkup{}
+	explorer := recipient.GetExplorer()
+
+	buttons := []telebot.Btn{
+		inlineKeyboard.URL("Seller on "+explorer.Name, explorer.AccountURL(sellTx.Seller)),
 	}
+	if dexURL != "" {
+		buttons = append(buttons, inlineKeyboard.URL("Chart", dexURL))
+	}
+
+	inlineKeyboard.Inline(inlineKeyboard.Row(buttons...))
 
 	opts := &telebot.SendOptions{
-		ParseMode:   "HTML",
-		ThreadID:    threadId,
-		ReplyMarkup: inlineKeyboard,
+		ParseMode:             "HTML",
+		ThreadID:              threadId,
+		ReplyMarkup:           inlineKeyboard,
+		DisableWebPagePreview: true,
 	}
 
-	_, err := s.bot.Bot.Send(recipient, animation, caption, opts)
+	_, err := s.bot.Bot.Send(recipient, text, opts)
 	if err != nil {
-		s.logger.Error("failed to send animation to chat %d: %s", recipient.Id, err)
+		s.logger.Error("failed to send sell message to chat %d: %s", recipient.Id, err)
 	}
 }
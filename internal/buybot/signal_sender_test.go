@@ -0,0 +1,158 @@
+package buybot
+
+import (
+	"consul-telegram-bot/internal/config"
+	"consul-telegram-bot/internal/logger"
+	"consul-telegram-bot/internal/model"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testBuy(signature string, buyer string, amount int64, solAmount float64, newHolder bool) *BuyTransaction {
+	return &BuyTransaction{
+		Mint:      "Mint",
+		Signature: signature,
+		Buyer:     buyer,
+		Amount:    big.NewInt(amount),
+		Decimals:  6,
+		SolAmount: solAmount,
+		NewHolder: newHolder,
+	}
+}
+
+func TestSelectBuySignalFiltersBeforePickingLargest(t *testing.T) {
+	useTestStore(t)
+
+	ignored := &model.WalletLabel{ChatID: 1, Address: "Whale", Ignored: true}
+	if err := ignored.Save(); err != nil {
+		t.Fatalf("failed to save wallet label: %s", err)
+	}
+
+	buys := []*BuyTransaction{
+		testBuy("whale", "Whale", 9_000_000, 9, true),
+		testBuy("small", "Small", 1_000_000, 0.1, true),
+		testBuy("medium", "Medium", 5_000_000, 2, false),
+		testBuy("fresh", "Fresh", 3_000_000, 1, true),
+		testBuy("dust", "Dust", 20_000_000, 0.001, true),
+	}
+
+	tests := []struct {
+		name      string
+		recipient *model.Recipient
+		want      string
+	}{
+		{name: "no filters", recipient: &model.Recipient{Id: 2}, want: "whale"},
+		{name: "ignored buyer", recipient: &model.Recipient{Id: 1}, want: "medium"},
+		{name: "minimum buy", recipient: &model.Recipient{Id: 3, MinBuySol: 0.5}, want: "whale"},
+		{name: "minimum buy below the default", recipient: &model.Recipient{Id: 3, MinBuySol: 0.0005}, want: "dust"},
+		{name: "ignored buyer and new holders only", recipient: &model.Recipient{Id: 1, NewHoldersOnly: true}, want: "fresh"},
+		{name: "nothing passes", recipient: &model.Recipient{Id: 4, MinBuySol: 100}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sender := NewSignalSender(nil, logger.New(), &config.Config{})
+
+			sent, skipped := sender.SelectBuySignal(tt.recipient, buys)
+
+			if tt.want == "" {
+				if sent != nil {
+					t.Fatalf("sent %s, want nothing", sent.Signature)
+				}
+				if len(skipped) != len(buys) {
+					t.Errorf("skipped %d buys, want %d", len(skipped), len(buys))
+				}
+				return
+			}
+
+			if sent == nil || sent.Signature != tt.want {
+				t.Fatalf("sent %+v, want %s", sent, tt.want)
+			}
+			if len(skipped) != len(buys)-1 {
+				t.Errorf("skipped %d buys, want %d", len(skipped), len(buys)-1)
+			}
+		})
+	}
+}
+
+func TestSelectBuySignalThrottlesPerChat(t *testing.T) {
+	useTestStore(t)
+
+	now := time.Unix(1_700_000_000, 0)
+	sender := NewSignalSender(nil, logger.New(), &config.Config{})
+	sender.SetClock(func() time.Time {
+		return now
+	})
+
+	first := &model.Recipient{Id: 1}
+	second := &model.Recipient{Id: 2}
+
+	if sent, _ := sender.SelectBuySignal(first, []*BuyTransaction{testBuy("a", "A", 5_000_000, 1, false)}); sent == nil {
+		t.Fatal("first buy to chat 1 was not sent")
+	}
+
+	now = now.Add(10 * time.Second)
+
+	sent, skipped := sender.SelectBuySignal(first, []*BuyTransaction{testBuy("b", "B", 4_000_000, 1, false)})
+	if sent != nil || skipped["b"] != "throttled" {
+		t.Fatalf("smaller buy within a minute: sent %+v, skipped %v, want throttled", sent, skipped)
+	}
+
+	if sent, _ := sender.SelectBuySignal(second, []*BuyTransaction{testBuy("b", "B", 4_000_000, 1, false)}); sent == nil {
+		t.Fatal("chat 2 was throttled by a signal sent to chat 1")
+	}
+
+	if sent, _ := sender.SelectBuySignal(first, []*BuyTransaction{testBuy("c", "C", 6_000_000, 1, false)}); sent == nil {
+		t.Fatal("larger buy within a minute was throttled")
+	}
+
+	now = now.Add(time.Minute)
+
+	if sent, _ := sender.SelectBuySignal(first, []*BuyTransaction{testBuy("d", "D", 1_000_000, 1, false)}); sent == nil {
+		t.Fatal("buy after the throttle window was not sent")
+	}
+}
+
+func TestRenderBuyMessageFallsBackToDefaultFormat(t *testing.T) {
+	useTestStore(t)
+
+	sender := NewSignalSender(nil, logger.New(), &config.Config{})
+	recipient := &model.Recipient{Id: 1, BuyTemplate: "<b>{buyer}</b> bought"}
+
+	short := testBuy("short", "Buyer", 5_000_000, 1, false)
+	if got := sender.renderBuyMessage(recipient, short, "TKN", nil, nil); got != "<b>Buyer</b> bought" {
+		t.Errorf("renderBuyMessage() = %q, want the custom template", got)
+	}
+
+	long := testBuy("long", strings.Repeat("B", maxCaptionLength), 5_000_000, 1, false)
+	if got := sender.renderBuyMessage(recipient, long, "TKN", nil, nil); !strings.HasPrefix(got, "<b>$TKN BUY") {
+		t.Errorf("renderBuyMessage() = %q, want the default format for a caption over the limit", got)
+	}
+}
+
+func TestBuyButtonsFallBackToDefaultButtons(t *testing.T) {
+	sender := NewSignalSender(nil, logger.New(), &config.Config{DexURL: "https://dexscreener.com/solana/pair"})
+	values := map[string]string{"buyer": "Buyer"}
+
+	tests := []struct {
+		name    string
+		buttons []model.BuyButton
+		wantURL string
+	}{
+		{name: "valid URL", buttons: []model.BuyButton{{Text: "Buyer", URL: "https://example.com/{buyer}"}}, wantURL: "https://example.com/Buyer"},
+		{name: "placeholder renders an invalid URL", buttons: []model.BuyButton{{Text: "Buyer", URL: "{buyer}"}}, wantURL: "https://dexscreener.com/solana/pair"},
+		{name: "no custom buttons", wantURL: "https://dexscreener.com/solana/pair"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buttons := sender.buyButtons(&model.Recipient{Id: 1, BuyButtons: tt.buttons}, values)
+
+			if len(buttons) != 1 || buttons[0].URL != tt.wantURL {
+				t.Errorf("buyButtons() = %+v, want a single button to %s", buttons, tt.wantURL)
+			}
+		})
+	}
+}
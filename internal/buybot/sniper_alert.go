@@ -0,0 +1,99 @@
+package buybot
+
+import (
+	"consul-telegram-bot/internal/model"
+	"consul-telegram-bot/internal/utils"
+
+	"fmt"
+	"strings"
+
+	telebot "gopkg.in/telebot.v3"
+)
+
+const sniperAlertMaxWallets = 10
+
+func (s *SignalSender) hasSniperRecipients(mint string) bool {
+	recipients, err := s.getAllRecipients()
+	if err != nil {
+		return false
+	}
+
+	for _, recipient := range recipients {
+		if recipient.GetThreadIdForSignalType(model.SignalTypeSnipers) != 0 &&
+			model.GetWithFallback(recipient.TokenAddress, s.config.TokenAddress) == mint {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *SignalSender) SendSniperAlert(alert *SniperAlert) {
+	s.logger.Info("sending sniper alert for token %s", alert.Mint)
+
+	recipients, err := s.getAllRecipients()
+	if err != nil {
+		s.logger.Error("failed to get recipients: %s", err)
+		return
+	}
+
+	for _, recipient := range recipients {
+		threadId := recipient.GetThreadIdForSignalType(model.SignalTypeSnipers)
+
+		if threadId == 0 {
+			continue
+		}
+
+		if model.GetWithFallback(recipient.TokenAddress, s.config.TokenAddress) != alert.Mint {
+			continue
+		}
+
+		ticker := model.GetWithFallback(recipient.TokenTicker, s.config.TokenTicker)
+
+		opts := &telebot.SendOptions{
+			ParseMode:             "HTML",
+			ThreadID:              threadId,
+			DisableWebPagePreview: true,
+		}
+
+		s.logger.Info("sending sniper alert to chat %d, thread %d", recipient.Id, threadId)
+		if _, err := s.bot.Bot.Send(recipient, s.formatSniperAlert(alert, ticker, recipient), opts); err != nil {
+			s.logger.Error("failed to send sniper alert to chat %d: %s", recipient.Id, err)
+		}
+	}
+}
+
+func (s *SignalSender) formatSniperAlert(alert *SniperAlert, ticker string, recipient *model.Recipient) string {
+	if ticker == "" {
+		ticker = "TOKEN"
+	}
+
+	explorer := recipient.GetExplorer()
+
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("<b>$%s SNIPER WARNING 🎯</b>\n\n", ticker))
+	sb.WriteString(fmt.Sprintf("<b>📦 Supply sniped:</b> %s (%s)\n", utils.FormatPercentage(alert.Percent, 2), utils.FormatTokenAmount(alert.Amount, alert.Decimals, ticker)))
+	sb.WriteString(fmt.Sprintf("<b>👥 Wallets:</b> %d\n\n", len(alert.Buyers)))
+
+	for _, group := range alert.Groups {
+		switch group.Kind {
+		case SniperGroupSlot:
+			sb.WriteString(fmt.Sprintf("• %d wallets bought in slot %d (%s)\n", len(group.Buyers), group.Slot, utils.FormatPercentage(group.Percent, 2)))
+		case SniperGroupFunder:
+			sb.WriteString(fmt.Sprintf("• %d fresh wallets funded by <a href=\"%s\">%s</a> (%s)\n", len(group.Buyers), explorer.AccountURL(group.Funder), utils.EscapeHTML(walletName(recipient, group.Funder)), utils.FormatPercentage(group.Percent, 2)))
+		}
+	}
+
+	sb.WriteString("\n")
+
+	for i, buyer := range alert.Buyers {
+		if i == sniperAlertMaxWallets {
+			sb.WriteString(fmt.Sprintf("…and %d more\n", len(alert.Buyers)-sniperAlertMaxWallets))
+			break
+		}
+		sb.WriteString(fmt.Sprintf("🦊 <a href=\"%s\">%s</a>\n", explorer.AccountURL(buyer), utils.EscapeHTML(walletName(recipient, buyer))))
+	}
+
+	return strings.TrimSuffix(sb.String(), "\n")
+}
@@ -0,0 +1,417 @@
+package buybot
+
+import (
+	"consul-telegram-bot/internal/config"
+	"consul-telegram-bot/internal/logger"
+	"context"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	SniperGroupSlot   = "slot"
+	SniperGroupFunder = "funder"
+
+	sniperFundingLookback  = 10
+	sniperMaxLookups       = 20
+	sniperLookupTimeout    = 30 * time.Second
+	sniperFundingCacheSize = 10000
+	sniperSupplyTTL        = time.Hour
+)
+
+type SniperConfig struct {
+	Window          time.Duration
+	MinSlotBuys     int
+	MinSharedFunder int
+	FreshWallet     time.Duration
+	SupplyPercent   float64
+}
+
+type SniperGroup struct {
+	Kind    string
+	Slot    uint64
+	Funder  string
+	Buyers  []string
+	Amount  *big.Int
+	Percent float64
+}
+
+type SniperAlert struct {
+	Mint       string
+	Decimals   int
+	Groups     []*SniperGroup
+	Buyers     []string
+	Signatures []string
+	Amount     *big.Int
+	Percent    float64
+}
+
+type walletFunding struct {
+	Funder string
+	Fresh  bool
+}
+
+type tokenSupply struct {
+	Amount    *big.Int
+	FetchedAt time.Time
+}
+
+type SniperDetector struct {
+	client   *HeliusClient
+	logger   *logger.Logger
+	config   SniperConfig
+	onAlert  func(*SniperAlert)
+	buys     map[string][]*BuyTransaction
+	alerted  map[string]bool
+	fundings map[string]*walletFunding
+	supplies map[string]*tokenSupply
+	mu       sync.Mutex
+}
+
+func NewSniperConfig(cfg *config.Config) SniperConfig {
+	return SniperConfig{
+		Window:          time.Duration(cfg.SniperWindowSeconds) * time.Second,
+		MinSlotBuys:     cfg.SniperMinSlotBuys,
+		MinSharedFunder: cfg.SniperMinSharedFunder,
+		FreshWallet:     time.Duration(cfg.SniperFreshWalletMinutes) * time.Minute,
+		SupplyPercent:   cfg.SniperSupplyPercent,
+	}
+}
+
+func NewSniperDetector(client *HeliusClient, logger *logger.Logger, cfg SniperConfig) *SniperDetector {
+	return &SniperDetector{
+		client:   client,
+		logger:   logger,
+		config:   cfg,
+		buys:     make(map[string][]*BuyTransaction),
+		alerted:  make(map[string]bool),
+		fundings: make(map[string]*walletFunding),
+		supplies: make(map[string]*tokenSupply),
+	}
+}
+
+func (d *SniperDetector) SetAlertHandler(handler func(*SniperAlert)) {
+	d.onAlert = handler
+}
+
+func (d *SniperDetector) Observe(buyTxs []*BuyTransaction) {
+	if d.config.MinSlotBuys <= 0 && d.config.MinSharedFunder <= 0 {
+		return
+	}
+
+	byMint := make(map[string][]*BuyTransaction)
+	for _, buyTx := range buyTxs {
+		byMint[buyTx.Mint] = append(byMint[buyTx.Mint], buyTx)
+	}
+
+	for mint, mintBuys := range byMint {
+		d.observeMint(mint, mintBuys)
+	}
+}
+
+func (d *SniperDetector) observeMint(mint string, buyTxs []*BuyTransaction) {
+	ctx, cancel := context.WithTimeout(context.Background(), sniperLookupTimeout)
+	defer cancel()
+
+	buys, unresolved := d.addBuys(mint, buyTxs)
+	if len(buys) == 0 {
+		return
+	}
+
+	for _, buyer := range unresolved {
+		funding, err := d.resolveFunding(ctx, buyer)
+		if err != nil {
+			d.logger.Warning("failed to resolve funding for %s: %s", buyer, err)
+			continue
+		}
+
+		d.mu.Lock()
+		if len(d.fundings) >= sniperFundingCacheSize {
+			d.fundings = make(map[string]*walletFunding)
+		}
+		d.fundings[buyer] = funding
+		d.mu.Unlock()
+	}
+
+	supply, err := d.tokenSupply(ctx, mint)
+	if err != nil {
+		d.logger.Warning("failed to get supply of %s for sniper detection: %s", mint, err)
+		return
+	}
+
+	d.mu.Lock()
+	var candidates []*BuyTransaction
+	for _, buyTx := range d.buys[mint] {
+		if !d.alerted[buyTx.Signature] {
+			candidates = append(candidates, buyTx)
+		}
+	}
+
+	alert := detectSnipers(candidates, d.fundings, supply, d.config)
+	if alert != nil {
+		for _, signature := range alert.Signatures {
+			d.alerted[signature] = true
+		}
+	}
+	d.mu.Unlock()
+
+	if alert == nil {
+		return
+	}
+
+	d.logger.Info("snipers detected on %s: %d wallets hold %.2f%% of supply", mint, len(alert.Buyers), alert.Percent)
+
+	if d.onAlert != nil {
+		d.onAlert(alert)
+	}
+}
+
+func (d *SniperDetector) addBuys(mint string, buyTxs []*BuyTransaction) ([]*BuyTransaction, []string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	buys := append(d.buys[mint], buyTxs...)
+
+	var latest int64
+	for _, buyTx := range buys {
+		if buyTime(buyTx) > latest {
+			latest = buyTime(buyTx)
+		}
+	}
+
+	cutoff := latest - int64(d.config.Window/time.Second)
+	kept := buys[:0]
+	for _, buyTx := range buys {
+		if buyTime(buyTx) < cutoff {
+			delete(d.alerted, buyTx.Signature)
+			continue
+		}
+		kept = append(kept, buyTx)
+	}
+
+	if len(kept) == 0 {
+		delete(d.buys, mint)
+		return nil, nil
+	}
+	d.buys[mint] = kept
+
+	if d.config.MinSharedFunder <= 0 {
+		return kept, nil
+	}
+
+	// Buyers left over by the lookup cap of an earlier batch are still in
+	// kept, so they get resolved on one of the following batches.
+	var unresolved []string
+	seen := make(map[string]bool)
+	for _, buyTx := range kept {
+		if seen[buyTx.Buyer] || d.fundings[buyTx.Buyer] != nil {
+			continue
+		}
+		seen[buyTx.Buyer] = true
+		unresolved = append(unresolved, buyTx.Buyer)
+
+		if len(unresolved) == sniperMaxLookups {
+			break
+		}
+	}
+
+	return kept, unresolved
+}
+
+func (d *SniperDetector) tokenSupply(ctx context.Context, mint string) (*big.Int, error) {
+	d.mu.Lock()
+	cached := d.supplies[mint]
+	d.mu.Unlock()
+
+	if cached != nil && time.Since(cached.FetchedAt) < sniperSupplyTTL {
+		return cached.Amount, nil
+	}
+
+	supply, _, err := d.client.GetTokenSupply(ctx, mint)
+	if err != nil {
+		return nil, err
+	}
+
+	d.mu.Lock()
+	d.supplies[mint] = &tokenSupply{Amount: supply, FetchedAt: time.Now()}
+	d.mu.Unlock()
+
+	return supply, nil
+}
+
+func (d *SniperDetector) resolveFunding(ctx context.Context, wallet string) (*walletFunding, error) {
+	signatures, err := d.client.GetSignaturesForAddress(ctx, wallet, SignaturesOptions{Limit: sniperFundingLookback})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(signatures) == 0 || len(signatures) >= sniperFundingLookback {
+		return &walletFunding{}, nil
+	}
+
+	oldest := signatures[len(signatures)-1]
+	if oldest.BlockTime == nil || time.Since(time.Unix(*oldest.BlockTime, 0)) > d.config.FreshWallet {
+		return &walletFunding{}, nil
+	}
+
+	tx, err := d.client.GetTransaction(ctx, oldest.Signature)
+	if err != nil {
+		return nil, err
+	}
+
+	funder := fundingSource(tx, wallet)
+
+	return &walletFunding{Funder: funder, Fresh: funder != ""}, nil
+}
+
+func fundingSource(tx *TransactionResponse, wallet string) string {
+	if tx == nil || tx.Meta == nil {
+		return ""
+	}
+
+	keys := tx.AccountKeys()
+	pre, post := tx.Meta.PreBalances, tx.Meta.PostBalances
+
+	received := false
+	for i, key := range keys {
+		if key == wallet && i < len(pre) && i < len(post) && post[i] > pre[i] {
+			received = true
+			break
+		}
+	}
+	if !received {
+		return ""
+	}
+
+	var funder string
+	var largestDecrease uint64
+	for i, key := range keys {
+		if key == wallet || i >= len(pre) || i >= len(post) || pre[i] <= post[i] {
+			continue
+		}
+		if decrease := pre[i] - post[i]; decrease > largestDecrease {
+			funder = key
+			largestDecrease = decrease
+		}
+	}
+
+	return funder
+}
+
+func detectSnipers(buys []*BuyTransaction, fundings map[string]*walletFunding, supply *big.Int, cfg SniperConfig) *SniperAlert {
+	if len(buys) == 0 || supply == nil || supply.Sign() == 0 {
+		return nil
+	}
+
+	var groups []*SniperGroup
+	flagged := make(map[string]*BuyTransaction)
+
+	if cfg.MinSlotBuys > 0 {
+		bySlot := make(map[uint64][]*BuyTransaction)
+		for _, buyTx := range buys {
+			if buyTx.Slot != 0 {
+				bySlot[buyTx.Slot] = append(bySlot[buyTx.Slot], buyTx)
+			}
+		}
+
+		for slot, slotBuys := range bySlot {
+			if group := newSniperGroup(slotBuys, supply, cfg.MinSlotBuys); group != nil {
+				group.Kind = SniperGroupSlot
+				group.Slot = slot
+				groups = append(groups, group)
+				flagBuys(flagged, slotBuys)
+			}
+		}
+	}
+
+	if cfg.MinSharedFunder > 0 {
+		byFunder := make(map[string][]*BuyTransaction)
+		for _, buyTx := range buys {
+			funding := fundings[buyTx.Buyer]
+			if funding != nil && funding.Fresh && funding.Funder != "" {
+				byFunder[funding.Funder] = append(byFunder[funding.Funder], buyTx)
+			}
+		}
+
+		for funder, funderBuys := range byFunder {
+			if group := newSniperGroup(funderBuys, supply, cfg.MinSharedFunder); group != nil {
+				group.Kind = SniperGroupFunder
+				group.Funder = funder
+				groups = append(groups, group)
+				flagBuys(flagged, funderBuys)
+			}
+		}
+	}
+
+	if len(groups) == 0 {
+		return nil
+	}
+
+	alert := &SniperAlert{
+		Mint:   buys[0].Mint,
+		Groups: groups,
+		Amount: new(big.Int),
+	}
+
+	seenBuyers := make(map[string]bool)
+	for signature, buyTx := range flagged {
+		alert.Signatures = append(alert.Signatures, signature)
+		alert.Amount.Add(alert.Amount, buyTx.Amount)
+		alert.Decimals = buyTx.Decimals
+
+		if !seenBuyers[buyTx.Buyer] {
+			seenBuyers[buyTx.Buyer] = true
+			alert.Buyers = append(alert.Buyers, buyTx.Buyer)
+		}
+	}
+
+	alert.Percent = percentOf(alert.Amount, supply)
+	if alert.Percent < cfg.SupplyPercent {
+		return nil
+	}
+
+	sort.Strings(alert.Signatures)
+	sort.Strings(alert.Buyers)
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Percent > groups[j].Percent
+	})
+
+	return alert
+}
+
+func newSniperGroup(buys []*BuyTransaction, supply *big.Int, minBuyers int) *SniperGroup {
+	group := &SniperGroup{Amount: new(big.Int)}
+
+	seen := make(map[string]bool)
+	for _, buyTx := range buys {
+		group.Amount.Add(group.Amount, buyTx.Amount)
+		if !seen[buyTx.Buyer] {
+			seen[buyTx.Buyer] = true
+			group.Buyers = append(group.Buyers, buyTx.Buyer)
+		}
+	}
+
+	if len(group.Buyers) < minBuyers {
+		return nil
+	}
+
+	sort.Strings(group.Buyers)
+	group.Percent = percentOf(group.Amount, supply)
+
+	return group
+}
+
+func flagBuys(flagged map[string]*BuyTransaction, buys []*BuyTransaction) {
+	for _, buyTx := range buys {
+		flagged[buyTx.Signature] = buyTx
+	}
+}
+
+func buyTime(buyTx *BuyTransaction) int64 {
+	if buyTx.BlockTime == 0 {
+		return time.Now().Unix()
+	}
+	return buyTx.BlockTime
+}
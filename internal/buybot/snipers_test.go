@@ -0,0 +1,304 @@
+package buybot
+
+import (
+	"consul-telegram-bot/internal/logger"
+	"fmt"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var sniperTestConfig = SniperConfig{
+	Window:          time.Minute,
+	MinSlotBuys:     3,
+	MinSharedFunder: 3,
+	FreshWallet:     time.Hour,
+	SupplyPercent:   5,
+}
+
+func sniperBuy(signature string, buyer string, amount int64, slot uint64, blockTime int64) *BuyTransaction {
+	return &BuyTransaction{
+		Mint:      "Mint",
+		Signature: signature,
+		Buyer:     buyer,
+		Amount:    big.NewInt(amount),
+		Decimals:  6,
+		Slot:      slot,
+		BlockTime: blockTime,
+	}
+}
+
+func TestDetectSnipers(t *testing.T) {
+	supply := big.NewInt(1_000_000_000)
+
+	fresh := map[string]*walletFunding{
+		"A": {Funder: "Dev", Fresh: true},
+		"B": {Funder: "Dev", Fresh: true},
+		"C": {Funder: "Dev", Fresh: true},
+	}
+	stale := map[string]*walletFunding{
+		"A": {Funder: "Dev"},
+		"B": {Funder: "Dev"},
+		"C": {Funder: "Dev"},
+	}
+
+	tests := []struct {
+		name        string
+		buys        []*BuyTransaction
+		fundings    map[string]*walletFunding
+		wantKinds   []string
+		wantBuyers  []string
+		wantPercent float64
+	}{
+		{
+			name: "three buyers in one slot",
+			buys: []*BuyTransaction{
+				sniperBuy("s1", "A", 20_000_000, 100, 1000),
+				sniperBuy("s2", "B", 20_000_000, 100, 1000),
+				sniperBuy("s3", "C", 20_000_000, 100, 1000),
+				sniperBuy("s4", "D", 90_000_000, 101, 1001),
+			},
+			wantKinds:   []string{SniperGroupSlot},
+			wantBuyers:  []string{"A", "B", "C"},
+			wantPercent: 6,
+		},
+		{
+			name: "one buyer twice in a slot counts once",
+			buys: []*BuyTransaction{
+				sniperBuy("s1", "A", 40_000_000, 100, 1000),
+				sniperBuy("s2", "A", 40_000_000, 100, 1000),
+				sniperBuy("s3", "B", 40_000_000, 100, 1000),
+			},
+		},
+		{
+			name: "fresh wallets with a shared funder across slots",
+			buys: []*BuyTransaction{
+				sniperBuy("s1", "A", 30_000_000, 100, 1000),
+				sniperBuy("s2", "B", 30_000_000, 105, 1002),
+				sniperBuy("s3", "C", 30_000_000, 110, 1004),
+			},
+			fundings:    fresh,
+			wantKinds:   []string{SniperGroupFunder},
+			wantBuyers:  []string{"A", "B", "C"},
+			wantPercent: 9,
+		},
+		{
+			name: "shared funder of old wallets",
+			buys: []*BuyTransaction{
+				sniperBuy("s1", "A", 30_000_000, 100, 1000),
+				sniperBuy("s2", "B", 30_000_000, 105, 1002),
+				sniperBuy("s3", "C", 30_000_000, 110, 1004),
+			},
+			fundings: stale,
+		},
+		{
+			name: "same slot and shared funder",
+			buys: []*BuyTransaction{
+				sniperBuy("s1", "A", 30_000_000, 100, 1000),
+				sniperBuy("s2", "B", 30_000_000, 100, 1000),
+				sniperBuy("s3", "C", 30_000_000, 100, 1000),
+			},
+			fundings:    fresh,
+			wantKinds:   []string{SniperGroupSlot, SniperGroupFunder},
+			wantBuyers:  []string{"A", "B", "C"},
+			wantPercent: 9,
+		},
+		{
+			name: "below the supply percent",
+			buys: []*BuyTransaction{
+				sniperBuy("s1", "A", 10_000_000, 100, 1000),
+				sniperBuy("s2", "B", 10_000_000, 100, 1000),
+				sniperBuy("s3", "C", 10_000_000, 100, 1000),
+			},
+		},
+		{
+			name: "unknown slots are not grouped",
+			buys: []*BuyTransaction{
+				sniperBuy("s1", "A", 30_000_000, 0, 1000),
+				sniperBuy("s2", "B", 30_000_000, 0, 1000),
+				sniperBuy("s3", "C", 30_000_000, 0, 1000),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alert := detectSnipers(tt.buys, tt.fundings, supply, sniperTestConfig)
+
+			if tt.wantKinds == nil {
+				if alert != nil {
+					t.Fatalf("got alert %+v, want none", alert)
+				}
+				return
+			}
+
+			if alert == nil {
+				t.Fatal("got no alert")
+			}
+
+			var kinds []string
+			for _, group := range alert.Groups {
+				kinds = append(kinds, group.Kind)
+			}
+			if len(kinds) != len(tt.wantKinds) {
+				t.Errorf("group kinds = %v, want %v", kinds, tt.wantKinds)
+			}
+			for _, want := range tt.wantKinds {
+				found := false
+				for _, kind := range kinds {
+					found = found || kind == want
+				}
+				if !found {
+					t.Errorf("group kinds = %v, missing %s", kinds, want)
+				}
+			}
+
+			if !reflect.DeepEqual(alert.Buyers, tt.wantBuyers) {
+				t.Errorf("Buyers = %v, want %v", alert.Buyers, tt.wantBuyers)
+			}
+			if alert.Percent != tt.wantPercent {
+				t.Errorf("Percent = %v, want %v", alert.Percent, tt.wantPercent)
+			}
+		})
+	}
+}
+
+func TestFundingSource(t *testing.T) {
+	tests := []struct {
+		name string
+		keys []string
+		pre  []uint64
+		post []uint64
+		want string
+	}{
+		{
+			name: "largest sender funds the wallet",
+			keys: []string{"Dev", "Wallet", "Fees"},
+			pre:  []uint64{10_000_000_000, 0, 1_000_000},
+			post: []uint64{8_999_995_000, 1_000_000_000, 900_000},
+			want: "Dev",
+		},
+		{
+			name: "wallet receives nothing",
+			keys: []string{"Wallet", "Pool"},
+			pre:  []uint64{2_000_000_000, 5_000_000_000},
+			post: []uint64{999_995_000, 6_000_000_000},
+		},
+		{
+			name: "wallet is not in the transaction",
+			keys: []string{"Dev", "Other"},
+			pre:  []uint64{2_000_000_000, 0},
+			post: []uint64{999_995_000, 1_000_000_000},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := &TransactionResponse{
+				Transaction: TransactionData{Message: MessageData{AccountKeys: tt.keys}},
+				Meta:        &TransactionMeta{PreBalances: tt.pre, PostBalances: tt.post},
+			}
+
+			if got := fundingSource(tx, "Wallet"); got != tt.want {
+				t.Errorf("fundingSource() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if got := fundingSource(&TransactionResponse{}, "Wallet"); got != "" {
+		t.Errorf("fundingSource() without meta = %q, want empty", got)
+	}
+}
+
+func TestSniperDetectorAddBuysPrunesWindow(t *testing.T) {
+	detector := NewSniperDetector(nil, logger.New(), sniperTestConfig)
+
+	detector.addBuys("Mint", []*BuyTransaction{
+		sniperBuy("old", "A", 1, 100, 1000),
+		sniperBuy("edge", "B", 1, 150, 1041),
+	})
+	detector.alerted["old"] = true
+
+	kept, unresolved := detector.addBuys("Mint", []*BuyTransaction{
+		sniperBuy("new", "C", 1, 200, 1100),
+		sniperBuy("new2", "C", 1, 201, 1101),
+	})
+
+	var signatures []string
+	for _, buyTx := range kept {
+		signatures = append(signatures, buyTx.Signature)
+	}
+	if !reflect.DeepEqual(signatures, []string{"edge", "new", "new2"}) {
+		t.Errorf("kept = %v, want [edge new new2]", signatures)
+	}
+	if detector.alerted["old"] {
+		t.Error("pruned buy is still marked as alerted")
+	}
+	if !reflect.DeepEqual(unresolved, []string{"B", "C"}) {
+		t.Errorf("unresolved = %v, want [B C]", unresolved)
+	}
+}
+
+func TestSniperDetectorAddBuysResolvesRemainingBuyersLater(t *testing.T) {
+	detector := NewSniperDetector(nil, logger.New(), sniperTestConfig)
+
+	var buyTxs []*BuyTransaction
+	for i := 0; i < sniperMaxLookups+5; i++ {
+		buyer := fmt.Sprintf("Buyer%d", i)
+		buyTxs = append(buyTxs, sniperBuy("sig"+buyer, buyer, 1, 100, 1000))
+	}
+
+	_, unresolved := detector.addBuys("Mint", buyTxs)
+	if len(unresolved) != sniperMaxLookups {
+		t.Fatalf("resolved %d buyers in the first batch, want the cap of %d", len(unresolved), sniperMaxLookups)
+	}
+	for _, buyer := range unresolved {
+		detector.fundings[buyer] = &walletFunding{}
+	}
+
+	_, unresolved = detector.addBuys("Mint", []*BuyTransaction{sniperBuy("late", "Buyer0", 1, 100, 1001)})
+	if len(unresolved) != 5 || unresolved[0] != fmt.Sprintf("Buyer%d", sniperMaxLookups) {
+		t.Errorf("unresolved = %v, want the 5 buyers left over by the cap", unresolved)
+	}
+}
+
+func TestSniperDetectorAlertsOncePerBuy(t *testing.T) {
+	cfg := sniperTestConfig
+	cfg.MinSharedFunder = 0
+
+	detector := NewSniperDetector(nil, logger.New(), cfg)
+	detector.supplies["Mint"] = &tokenSupply{Amount: big.NewInt(1_000_000_000), FetchedAt: time.Now()}
+
+	var alerts []*SniperAlert
+	detector.SetAlertHandler(func(alert *SniperAlert) {
+		alerts = append(alerts, alert)
+	})
+
+	detector.Observe([]*BuyTransaction{
+		sniperBuy("s1", "A", 20_000_000, 100, 1000),
+		sniperBuy("s2", "B", 20_000_000, 100, 1000),
+		sniperBuy("s3", "C", 20_000_000, 100, 1000),
+	})
+	if len(alerts) != 1 {
+		t.Fatalf("got %d alerts after the sniped slot, want 1", len(alerts))
+	}
+
+	detector.Observe([]*BuyTransaction{
+		sniperBuy("s4", "D", 20_000_000, 100, 1001),
+	})
+	if len(alerts) != 1 {
+		t.Fatalf("got %d alerts, the same buys were alerted twice", len(alerts))
+	}
+
+	detector.Observe([]*BuyTransaction{
+		sniperBuy("s5", "E", 20_000_000, 100, 1002),
+		sniperBuy("s6", "F", 20_000_000, 100, 1002),
+	})
+	if len(alerts) != 2 {
+		t.Fatalf("got %d alerts, want a second one for new buys in the slot", len(alerts))
+	}
+	if want := []string{"s4", "s5", "s6"}; !reflect.DeepEqual(alerts[1].Signatures, want) {
+		t.Errorf("second alert signatures = %v, want %v", alerts[1].Signatures, want)
+	}
+}
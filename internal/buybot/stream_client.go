@@ -0,0 +1,153 @@
+package buybot
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	streamHandshakeTimeout = 15 * time.Second
+	streamReadTimeout      = 90 * time.Second
+	streamPingInterval     = 30 * time.Second
+)
+
+type StreamClient struct {
+	wsURL  string
+	dialer *websocket.Dialer
+}
+
+type LogsNotification struct {
+	Signature string
+	Slot      uint64
+	Err       interface{}
+}
+
+type streamMessage struct {
+	ID     *uint64         `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *RPCError       `json:"error,omitempty"`
+	Method string          `json:"method"`
+	Params *struct {
+		Result struct {
+			Context struct {
+				Slot uint64 `json:"slot"`
+			} `json:"context"`
+			Value struct {
+				Signature string      `json:"signature"`
+				Err       interface{} `json:"err"`
+			} `json:"value"`
+		} `json:"result"`
+	} `json:"params,omitempty"`
+}
+
+func NewStreamClient(wsURL string) *StreamClient {
+	return &StreamClient{
+		wsURL: wsURL,
+		dialer: &websocket.Dialer{
+			HandshakeTimeout: streamHandshakeTimeout,
+		},
+	}
+}
+
+func (c *StreamClient) SubscribeLogs(mention string, stop <-chan struct{}, onSubscribed func(), onLogs func(LogsNotification)) error {
+	conn, _, err := c.dialer.Dial(c.wsURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	defer conn.Close()
+
+	request := RPCRequest{
+		Jsonrpc: "2.0",
+		ID:      1,
+		Method:  "logsSubscribe",
+		Params: []interface{}{
+			map[string]interface{}{
+				"mentions": []string{mention},
+			},
+			map[string]interface{}{
+				"commitment": "confirmed",
+			},
+		},
+	}
+
+	if err := conn.WriteJSON(request); err != nil {
+		return fmt.Errorf("failed to subscribe: %w", err)
+	}
+
+	conn.SetReadDeadline(time.Now().Add(streamReadTimeout))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(streamReadTimeout))
+	})
+
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		ticker := time.NewTicker(streamPingInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-stop:
+				conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+				conn.Close()
+				return
+			case <-ticker.C:
+				if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(10*time.Second)); err != nil {
+					conn.Close()
+					return
+				}
+			}
+		}
+	}()
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			select {
+			case <-stop:
+				return nil
+			default:
+				return fmt.Errorf("failed to read message: %w", err)
+			}
+		}
+
+		conn.SetReadDeadline(time.Now().Add(streamReadTimeout))
+
+		var msg streamMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			return fmt.Errorf("failed to unmarshal message: %w", err)
+		}
+
+		if msg.Error != nil {
+			return fmt.Errorf("RPC error: %s", msg.Error.Message)
+		}
+
+		if msg.ID != nil && *msg.ID == request.ID {
+			if onSubscribed != nil {
+				onSubscribed()
+			}
+			continue
+		}
+
+		if msg.Method != "logsNotification" || msg.Params == nil {
+			continue
+		}
+
+		value := msg.Params.Result.Value
+		if value.Signature == "" {
+			continue
+		}
+
+		onLogs(LogsNotification{
+			Signature: value.Signature,
+			Slot:      msg.Params.Result.Context.Slot,
+			Err:       value.Err,
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package buybot
+
+import (
+	"consul-telegram-bot/internal/logger"
+	"consul-telegram-bot/internal/store"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func newFakeStreamServer(t *testing.T, handle func(conn *websocket.Conn)) string {
+	t.Helper()
+
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("failed to upgrade: %s", err)
+			return
+		}
+		defer conn.Close()
+
+		var request RPCRequest
+		if err := conn.ReadJSON(&request); err != nil {
+			t.Errorf("failed to read subscribe request: %s", err)
+			return
+		}
+		if request.Method != "logsSubscribe" {
+			t.Errorf("method = %q, want logsSubscribe", request.Method)
+		}
+
+		handle(conn)
+	}))
+	t.Cleanup(server.Close)
+
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+func logsNotification(signature string, slot uint64, err interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"jsonrpc": "2.0",
+		"method":  "logsNotification",
+		"params": map[string]interface{}{
+			"subscription": 7,
+			"result": map[string]interface{}{
+				"context": map[string]interface{}{"slot": slot},
+				"value": map[string]interface{}{
+					"signature": signature,
+					"err":       err,
+					"logs":      []string{},
+				},
+			},
+		},
+	}
+}
+
+func TestSubscribeLogsDeliversNotifications(t *testing.T) {
+	wsURL := newFakeStreamServer(t, func(conn *websocket.Conn) {
+		conn.WriteJSON(map[string]interface{}{"jsonrpc": "2.0", "result": 7, "id": 1})
+		conn.WriteJSON(logsNotification("", 10, nil))
+		conn.WriteJSON(logsNotification("sig1", 11, nil))
+		conn.WriteJSON(logsNotification("sig2", 12, map[string]interface{}{"InstructionError": []interface{}{0, "Custom"}}))
+
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	})
+
+	stop := make(chan struct{})
+	received := make(chan LogsNotification, 10)
+	var subscribed atomic.Bool
+
+	done := make(chan error, 1)
+	go func() {
+		done <- NewStreamClient(wsURL).SubscribeLogs("Mint", stop, func() {
+			subscribed.Store(true)
+		}, func(notification LogsNotification) {
+			received <- notification
+		})
+	}()
+
+	var notifications []LogsNotification
+	for len(notifications) < 2 {
+		select {
+		case notification := <-received:
+			notifications = append(notifications, notification)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for notifications, got %d", len(notifications))
+		}
+	}
+	close(stop)
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("SubscribeLogs returned %v after stop, want nil", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("SubscribeLogs did not return after stop")
+	}
+
+	if !subscribed.Load() {
+		t.Error("onSubscribed was not called")
+	}
+	if notifications[0].Signature != "sig1" || notifications[0].Slot != 11 || notifications[0].Err != nil {
+		t.Errorf("first notification = %+v", notifications[0])
+	}
+	if notifications[1].Signature != "sig2" || notifications[1].Slot != 12 || notifications[1].Err == nil {
+		t.Errorf("second notification = %+v", notifications[1])
+	}
+}
+
+func TestSubscribeLogsReturnsRPCError(t *testing.T) {
+	wsURL := newFakeStreamServer(t, func(conn *websocket.Conn) {
+		conn.WriteJSON(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      1,
+			"error":   map[string]interface{}{"code": -32602, "message": "invalid mentions"},
+		})
+	})
+
+	err := NewStreamClient(wsURL).SubscribeLogs("Mint", make(chan struct{}), nil, func(LogsNotification) {})
+	if err == nil || !strings.Contains(err.Error(), "invalid mentions") {
+		t.Fatalf("err = %v, want RPC error", err)
+	}
+}
+
+func TestSubscribeLogsReturnsErrorWhenConnectionDrops(t *testing.T) {
+	wsURL := newFakeStreamServer(t, func(conn *websocket.Conn) {
+		conn.WriteJSON(map[string]interface{}{"jsonrpc": "2.0", "result": 7, "id": 1})
+	})
+
+	err := NewStreamClient(wsURL).SubscribeLogs("Mint", make(chan struct{}), nil, func(LogsNotification) {})
+	if err == nil {
+		t.Fatal("err = nil, want error after the server closed the connection")
+	}
+}
+
+func TestStreamedSignatureIsRetriedWhenTransactionIsMissing(t *testing.T) {
+	useTestStore(t)
+
+	var available atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request RPCRequest
+		json.NewDecoder(r.Body).Decode(&request)
+
+		var result interface{}
+		switch request.Method {
+		case "getTransaction":
+			if available.Load() {
+				result = map[string]interface{}{
+					"slot":        11,
+					"transaction": map[string]interface{}{"message": map[string]interface{}{"accountKeys": []string{"Buyer"}}},
+					"meta":        map[string]interface{}{"fee": 5000, "preBalances": []uint64{1}, "postBalances": []uint64{1}},
+				}
+			}
+		case "getSignaturesForAddress":
+			result = []map[string]interface{}{{"signature": "sig1", "slot": 11}}
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": request.ID, "result": result})
+	}))
+	defer server.Close()
+
+	log := logger.New()
+	monitor := NewMonitor(NewHeliusClient([]string{server.URL}, log), log, "Mint", 100)
+	monitor.lastSignature = "sig0"
+
+	monitor.handleStreamedLogs(LogsNotification{Signature: "sig1", Slot: 11})
+
+	if monitor.isProcessed("sig1") {
+		t.Fatal("sig1 was marked processed although the transaction could not be fetched")
+	}
+	if monitor.lastSignature != "sig0" {
+		t.Fatalf("lastSignature = %q after a streamed notification, want sig0", monitor.lastSignature)
+	}
+
+	available.Store(true)
+	monitor.Poll()
+
+	if !monitor.isProcessed("sig1") {
+		t.Fatal("sig1 was not processed by the next poll")
+	}
+	if monitor.lastSignature != "sig1" {
+		t.Fatalf("lastSignature = %q after poll, want sig1", monitor.lastSignature)
+	}
+}
+
+func useTestStore(t *testing.T) {
+	t.Helper()
+
+	storeInstance, err := store.New(t.TempDir(), false, false)
+	if err != nil {
+		t.Fatalf("failed to create store: %s", err)
+	}
+	storeInstance.MakeGlobal()
+}
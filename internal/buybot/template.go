@@ -0,0 +1,300 @@
+package buybot
+
+import (
+	"consul-telegram-bot/internal/config"
+	"consul-telegram-bot/internal/logger"
+	"consul-telegram-bot/internal/model"
+	"consul-telegram-bot/internal/utils"
+	"fmt"
+	"math/big"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	telebot "gopkg.in/telebot.v3"
+)
+
+const maxCaptionLength = 1024
+
+var BuyTemplatePlaceholders = []string{
+	"ticker",
+	"emoji",
+	"tier",
+	"amount",
+	"spent",
+	"sol",
+	"usd",
+	"price",
+	"mcap",
+	"buyer",
+	"buyer_short",
+	"buyer_label",
+	"buyer_url",
+	"buyer_link",
+	"holder",
+	"position",
+	"venue",
+	"tx",
+	"tx_link",
+	"signature",
+	"token_url",
+}
+
+var htmlPlaceholders = map[string]bool{
+	"tx_link":    true,
+	"buyer_link": true,
+}
+
+var placeholderPattern = regexp.MustCompile(`\{([a-zA-Z0-9_]+)\}`)
+
+const (
+	sampleMint     = "TokenMint1111111111111111111111111111111111"
+	sampleSolPrice = 150.0
+)
+
+func ValidateBuyTemplate(template string) error {
+	if err := checkPlaceholders(template); err != nil {
+		return err
+	}
+
+	return checkRenderedMessage(renderTemplate(template, sampleTemplateValues(), true))
+}
+
+func ValidateBuyButton(button model.BuyButton) error {
+	if button.Text == "" {
+		return fmt.Errorf("button text is empty")
+	}
+
+	if err := checkPlaceholders(button.Text + button.URL); err != nil {
+		return err
+	}
+
+	if !isButtonURL(renderTemplate(button.URL, sampleTemplateValues(), false)) {
+		return fmt.Errorf("invalid URL %q", button.URL)
+	}
+
+	return nil
+}
+
+func isButtonURL(value string) bool {
+	parsed, err := url.Parse(value)
+	if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http" && parsed.Scheme != "tg") || (parsed.Scheme != "tg" && parsed.Host == "") {
+		return false
+	}
+
+	return true
+}
+
+func PreviewSampleBuy(cfg *config.Config, logger *logger.Logger, recipient *model.Recipient) (string, []model.BuyButton) {
+	sender := NewSignalSender(nil, logger, cfg)
+	sender.SetPriceOracle(&StaticPriceOracle{
+		SolUSD: sampleSolPrice,
+		Tokens: map[string]TokenPrice{
+			sampleMint: {PriceUSD: 0.00042, Supply: 1_000_000_000},
+		},
+	})
+
+	buyTx := sampleBuyTransaction()
+	return sender.PreviewBuySignal(buyTx, recipient), sender.PreviewBuyButtons(buyTx, recipient)
+}
+
+func (s *SignalSender) PreviewBuyButtons(buyTx *BuyTransaction, recipient *model.Recipient) []model.BuyButton {
+	ticker := model.GetWithFallback(recipient.TokenTicker, s.config.TokenTicker)
+	tier := model.FindBuyTier(recipient.GetBuyTiers(), buyTx.SolAmount)
+
+	return s.buyButtons(recipient, buyTemplateValues(buyTx, ticker, tier, s.valueBuy(buyTx), recipient))
+}
+
+func (s *SignalSender) renderBuyMessage(recipient *model.Recipient, buyTx *BuyTransaction, ticker string, tier *model.BuyTier, valuation *buyValuation) string {
+	if recipient.BuyTemplate == "" {
+		return s.formatBuyMessage(buyTx, ticker, tier, valuation, recipient)
+	}
+
+	// The template was checked against sample values when it was set, but
+	// real buyer labels, tiers and amounts can still push it over the limit.
+	message := renderTemplate(recipient.BuyTemplate, buyTemplateValues(buyTx, ticker, tier, valuation, recipient), true)
+	if err := checkRenderedMessage(message); err != nil {
+		s.logger.Warning("buy template of chat %d rendered an invalid message for tx %s, using the default format: %s", recipient.Id, buyTx.Signature, err)
+		return s.formatBuyMessage(buyTx, ticker, tier, valuation, recipient)
+	}
+
+	return message
+}
+
+func checkRenderedMessage(message string) error {
+	if err := utils.ValidateTelegramHTML(message); err != nil {
+		return err
+	}
+
+	if length := utils.TelegramHTMLLength(message); length > maxCaptionLength {
+		return fmt.Errorf("message is %d characters long, the limit is %d", length, maxCaptionLength)
+	}
+
+	return nil
+}
+
+func (s *SignalSender) buyButtons(recipient *model.Recipient, values map[string]string) []model.BuyButton {
+	if len(recipient.BuyButtons) > 0 {
+		buttons, err := renderBuyButtons(recipient.BuyButtons, values)
+		if err == nil {
+			return buttons
+		}
+		s.logger.Warning("buy buttons of chat %d rendered an invalid URL, using the default buttons: %s", recipient.Id, err)
+	}
+
+	var buttons []model.BuyButton
+	if dexURL := model.GetWithFallback(recipient.DexURL, s.config.DexURL); dexURL != "" {
+		buttons = append(buttons, model.BuyButton{Text: "Buy on Dexscreener", URL: dexURL})
+	}
+	if axiomURL := model.GetWithFallback(recipient.AxiomURL, s.config.AxiomURL); axiomURL != "" {
+		buttons = append(buttons, model.BuyButton{Text: "Buy on Axiom", URL: axiomURL})
+	}
+	return buttons
+}
+
+func renderBuyButtons(templates []model.BuyButton, values map[string]string) ([]model.BuyButton, error) {
+	buttons := make([]model.BuyButton, 0, len(templates))
+	for _, button := range templates {
+		if values == nil && placeholderPattern.MatchString(button.Text+button.URL) {
+			continue
+		}
+
+		rendered := model.BuyButton{
+			Text: renderTemplate(button.Text, values, false),
+			URL:  renderTemplate(button.URL, values, false),
+		}
+		if !isButtonURL(rendered.URL) {
+			return nil, fmt.Errorf("invalid URL %q", rendered.URL)
+		}
+
+		buttons = append(buttons, rendered)
+	}
+	return buttons, nil
+}
+
+func (s *SignalSender) buyKeyboard(recipient *model.Recipient, values map[string]string) *telebot.ReplyMarkup {
+	inlineKeyboard := &telebot.ReplyMarkup{}
+
+	var buttons []telebot.Btn
+	for _, button := range s.buyButtons(recipient, values) {
+		buttons = append(buttons, inlineKeyboard.URL(button.Text, button.URL))
+	}
+
+	if len(buttons) > 0 {
+		inlineKeyboard.Inline(inlineKeyboard.Row(buttons...))
+	}
+
+	return inlineKeyboard
+}
+
+func buyTemplateValues(buyTx *BuyTransaction, ticker string, tier *model.BuyTier, valuation *buyValuation, recipient *model.Recipient) map[string]string {
+	if ticker == "" {
+		ticker = "TOKEN"
+	}
+
+	explorer := recipient.GetExplorer()
+	buyerName := walletName(recipient, buyTx.Buyer)
+
+	values := map[string]string{
+		"ticker":      ticker,
+		"emoji":       model.DefaultBuyTiers[0].Emoji,
+		"amount":      utils.FormatTokenAmount(buyTx.Amount, buyTx.Decimals, ticker),
+		"spent":       buyTx.FormatSpent(),
+		"sol":         utils.FormatNumber(buyTx.SolAmount, "SOL"),
+		"buyer":       buyTx.Buyer,
+		"buyer_short": utils.ShortenAddress(buyTx.Buyer),
+		"buyer_url":   explorer.AccountURL(buyTx.Buyer),
+		"buyer_label": buyerName,
+		"buyer_link":  fmt.Sprintf("<a href=\"%s\">%s</a>", explorer.AccountURL(buyTx.Buyer), utils.EscapeHTML(buyerName)),
+		"venue":       buyTx.Venue.String(),
+		"tx":          explorer.TxURL(buyTx.Signature),
+		"tx_link":     fmt.Sprintf("<a href=\"%s\">%s</a>", explorer.TxURL(buyTx.Signature), utils.ShortenAddress(buyTx.Signature)),
+		"signature":   buyTx.Signature,
+		"token_url":   explorer.TokenURL(buyTx.Mint),
+	}
+
+	if tier != nil {
+		values["tier"] = tier.Name
+		if tier.Emoji != "" {
+			values["emoji"] = tier.Emoji
+		}
+	}
+
+	if buyTx.Venue != nil && buyTx.Router != nil {
+		values["venue"] += " (" + buyTx.Router.Name + ")"
+	}
+
+	if valuation != nil {
+		if valuation.USD > 0 {
+			values["usd"] = utils.FormatUSD(valuation.USD)
+		}
+		if valuation.PriceUSD > 0 {
+			values["price"] = utils.FormatPrice(valuation.PriceUSD)
+		}
+		if valuation.MarketCap > 0 {
+			values["mcap"] = utils.FormatUSD(valuation.MarketCap)
+		}
+	}
+
+	if buyTx.NewHolder {
+		values["holder"] = "🆕 New holder"
+	} else if buyTx.PositionIncrease > 0 {
+		values["position"] = "+" + utils.FormatPercentage(buyTx.PositionIncrease, 2)
+		values["holder"] = "📈 Position " + values["position"]
+	}
+
+	return values
+}
+
+func renderTemplate(template string, values map[string]string, escape bool) string {
+	return placeholderPattern.ReplaceAllStringFunc(template, func(match string) string {
+		name := strings.ToLower(match[1 : len(match)-1])
+		value := values[name]
+		if escape && !htmlPlaceholders[name] {
+			return utils.EscapeHTML(value)
+		}
+		return value
+	})
+}
+
+func checkPlaceholders(template string) error {
+	known := make(map[string]bool, len(BuyTemplatePlaceholders))
+	for _, name := range BuyTemplatePlaceholders {
+		known[name] = true
+	}
+
+	var unknown []string
+	for _, match := range placeholderPattern.FindAllStringSubmatch(template, -1) {
+		if !known[strings.ToLower(match[1])] {
+			unknown = append(unknown, "{"+match[1]+"}")
+		}
+	}
+
+	if len(unknown) > 0 {
+		return fmt.Errorf("unknown placeholders: %s", strings.Join(unknown, ", "))
+	}
+
+	return nil
+}
+
+func sampleBuyTransaction() *BuyTransaction {
+	return &BuyTransaction{
+		Mint:             sampleMint,
+		Signature:        "5xSampLeSignaturE1111111111111111111111111111111111111111111111111111111111111111111",
+		Buyer:            "BuyeR1111111111111111111111111111111111111",
+		Amount:           big.NewInt(1_234_567_890_000),
+		Decimals:         6,
+		SolAmount:        2.5,
+		BlockTime:        time.Now().Unix(),
+		PositionIncrease: 12.5,
+		Venue:            &Venue{Key: model.VenueRaydium, Name: "Raydium CPMM"},
+		Router:           &jupiterVenue,
+	}
+}
+
+func sampleTemplateValues() map[string]string {
+	tier := model.DefaultBuyTiers[len(model.DefaultBuyTiers)-1]
+	valuation := &buyValuation{USD: 2.5 * sampleSolPrice, PriceUSD: 0.00042, MarketCap: 420_000}
+	return buyTemplateValues(sampleBuyTransaction(), "TOKEN", &tier, valuation, &model.Recipient{})
+}
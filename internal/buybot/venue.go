@@ -0,0 +1,114 @@
+package buybot
+
+import (
+	"consul-telegram-bot/internal/model"
+	"strings"
+)
+
+type Venue struct {
+	Key  string
+	Name string
+}
+
+var jupiterVenue = Venue{Key: model.VenueJupiter, Name: "Jupiter"}
+
+var venuePrograms = map[string]Venue{
+	"675kPX9MHTjS2zt1qfr1NYHuzeLXfQM9H24wFSUt1Mp8": {Key: model.VenueRaydium, Name: "Raydium AMM"},
+	"CAMMCzo5YL8w4VFF8KVHrK22GGUsp5VTaW7grrKgrWqK": {Key: model.VenueRaydium, Name: "Raydium CLMM"},
+	"CPMMoo8L3F4NbTegBCKVNunggL7H1ZpdTHKxQB5qKP1C": {Key: model.VenueRaydium, Name: "Raydium CPMM"},
+	"LanMV9sAd7wArD4vJFi2qDdfnVhFxYSUg6eADduJ3uj":  {Key: model.VenueRaydium, Name: "Raydium LaunchLab"},
+	"6EF8rrecthR5Dkzon8Nwu78hRvfCKubJ14M5uBEwF6P":  {Key: model.VenuePumpFun, Name: "Pump.fun"},
+	"pAMMBay6oceH9fJKBRHGP5D4bD4sWpmSwMn52FMfXEA":  {Key: model.VenuePumpSwap, Name: "PumpSwap"},
+	"LBUZKhRxPF3XUpBCjp4YzTKgLccjZhTSDM9YuVaPwxo":  {Key: model.VenueMeteora, Name: "Meteora DLMM"},
+	"Eo7WjKq67rjJQSZxS6z3YkapzY3eMj6Xy8X5EQVn5UaB": {Key: model.VenueMeteora, Name: "Meteora"},
+	"cpamdpZCGKUy5JxQXB4dcpGPiikHawvSWAd6mEn1sGG":  {Key: model.VenueMeteora, Name: "Meteora DAMM v2"},
+	"dbcij3LWUppWqq96dh6gJWwBifmcGfLSB5D4DuSMaqN":  {Key: model.VenueMeteora, Name: "Meteora DBC"},
+	"whirLbMiicVdio4qvUfM5KAg6Ct8VwpYzGff3uctyCc":  {Key: model.VenueOrca, Name: "Orca Whirlpool"},
+	"9W959DqEETiGZocYWCQPaJ6sBmUzgfxXfqGeTEdp3aQP": {Key: model.VenueOrca, Name: "Orca"},
+	"JUP6LkbZbjS1jKKwapdHNy74zcZ3tLUZoi5QNyVTaV4":  jupiterVenue,
+	"JUP4Fb2cqiRUcaTHdrPC8h2gNsA5ETXiPDD33WcGuJB":  jupiterVenue,
+}
+
+func (v *Venue) String() string {
+	if v == nil {
+		return ""
+	}
+	return v.Name
+}
+
+func classifyVenue(tx *TransactionResponse) (venue *Venue, router *Venue) {
+	for _, programID := range invokedPrograms(tx) {
+		known, ok := venuePrograms[programID]
+		if !ok {
+			continue
+		}
+
+		if known.Key == model.VenueJupiter {
+			if router == nil {
+				router = &known
+			}
+			continue
+		}
+
+		if venue == nil {
+			venue = &known
+		}
+	}
+
+	if venue == nil {
+		return router, nil
+	}
+
+	return venue, router
+}
+
+func invokedPrograms(tx *TransactionResponse) []string {
+	var programs []string
+	seen := make(map[string]bool)
+
+	add := func(programID string) {
+		if programID != "" && !seen[programID] {
+			seen[programID] = true
+			programs = append(programs, programID)
+		}
+	}
+
+	accountKeys := tx.AccountKeys()
+	addInstruction := func(instruction Instruction) {
+		if instruction.ProgramIdIndex >= 0 && instruction.ProgramIdIndex < len(accountKeys) {
+			add(accountKeys[instruction.ProgramIdIndex])
+		}
+	}
+
+	for _, instruction := range tx.Transaction.Message.Instructions {
+		addInstruction(instruction)
+	}
+
+	if tx.Meta != nil {
+		for _, inner := range tx.Meta.InnerInstructions {
+			for _, instruction := range inner.Instructions {
+				addInstruction(instruction)
+			}
+		}
+
+		for _, line := range tx.Meta.LogMessages {
+			fields := strings.Fields(line)
+			if len(fields) >= 3 && fields[0] == "Program" && fields[2] == "invoke" {
+				add(fields[1])
+			}
+		}
+	}
+
+	return programs
+}
+
+func (b *BuyTransaction) VenueKeys() []string {
+	var keys []string
+	if b.Venue != nil {
+		keys = append(keys, b.Venue.Key)
+	}
+	if b.Router != nil {
+		keys = append(keys, b.Router.Key)
+	}
+	return keys
+}
@@ -0,0 +1,69 @@
+package buybot
+
+import (
+	"consul-telegram-bot/internal/logger"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const fixtureMint = "9BB6NFEcjBCtnNLFko2FqVQBq8HHM13kCyYcdQbgpump"
+
+func loadTransactionFixture(t *testing.T, dir string, name string) *TransactionResponse {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", dir, name))
+	if err != nil {
+		t.Fatalf("failed to read fixture: %s", err)
+	}
+
+	var tx TransactionResponse
+	if err := json.Unmarshal(data, &tx); err != nil {
+		t.Fatalf("failed to unmarshal fixture: %s", err)
+	}
+
+	return &tx
+}
+
+func TestClassifyVenueFixtures(t *testing.T) {
+	tests := []struct {
+		fixture    string
+		wantVenue  string
+		wantRouter string
+	}{
+		{fixture: "raydium_amm.json", wantVenue: "Raydium AMM"},
+		{fixture: "pumpfun.json", wantVenue: "Pump.fun"},
+		{fixture: "pumpswap.json", wantVenue: "PumpSwap"},
+		{fixture: "meteora_dlmm_inner.json", wantVenue: "Meteora DLMM", wantRouter: "Jupiter"},
+		{fixture: "orca_logs_only.json", wantVenue: "Orca Whirlpool"},
+		{fixture: "jupiter_unknown_amm.json", wantVenue: "Jupiter"},
+		{fixture: "unknown.json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			venue, router := classifyVenue(loadTransactionFixture(t, "venues", tt.fixture))
+
+			if venue.String() != tt.wantVenue {
+				t.Errorf("venue = %q, want %q", venue.String(), tt.wantVenue)
+			}
+			if router.String() != tt.wantRouter {
+				t.Errorf("router = %q, want %q", router.String(), tt.wantRouter)
+			}
+		})
+	}
+}
+
+func TestAnalyzeBuyTransactionSetsVenue(t *testing.T) {
+	monitor := NewMonitor(nil, logger.New(), fixtureMint, 100)
+
+	buyTx := monitor.analyzeBuyTransaction(loadTransactionFixture(t, "venues", "meteora_dlmm_inner.json"), "5fixture")
+	if buyTx == nil {
+		t.Fatal("got no buy")
+	}
+
+	if got := buyTx.VenueKeys(); len(got) != 2 || got[0] != "meteora" || got[1] != "jupiter" {
+		t.Errorf("VenueKeys() = %v, want [meteora jupiter]", got)
+	}
+}
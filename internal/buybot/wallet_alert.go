@@ -0,0 +1,103 @@
+package buybot
+
+import (
+	"consul-telegram-bot/internal/model"
+	"consul-telegram-bot/internal/utils"
+
+	"fmt"
+	"strings"
+
+	telebot "gopkg.in/telebot.v3"
+)
+
+func (s *SignalSender) SendWalletMovement(movement *WalletMovement) {
+	s.logger.Info("sending wallet movement for %s: %s (token %s)", movement.Wallet, movement.Kind, movement.Mint)
+
+	recipients, err := s.getAllRecipients()
+	if err != nil {
+		s.logger.Error("failed to get recipients: %s", err)
+		return
+	}
+
+	for _, recipient := range recipients {
+		threadId := recipient.GetThreadIdForSignalType(model.SignalTypeWallets)
+
+		if threadId == 0 {
+			continue
+		}
+
+		if model.GetWithFallback(recipient.TokenAddress, s.config.TokenAddress) != movement.Mint {
+			continue
+		}
+
+		watched, err := model.FindWatchedWallet(recipient.Id, movement.Wallet)
+		if err != nil {
+			continue
+		}
+
+		ticker := model.GetWithFallback(recipient.TokenTicker, s.config.TokenTicker)
+
+		opts := &telebot.SendOptions{
+			ParseMode:             "HTML",
+			ThreadID:              threadId,
+			DisableWebPagePreview: true,
+		}
+
+		s.logger.Info("sending wallet movement to chat %d, thread %d", recipient.Id, threadId)
+		if _, err := s.bot.Bot.Send(recipient, s.formatWalletMovement(movement, watched, ticker, recipient), opts); err != nil {
+			s.logger.Error("failed to send wallet movement to chat %d: %s", recipient.Id, err)
+		}
+	}
+}
+
+func (s *SignalSender) formatWalletMovement(movement *WalletMovement, watched *model.WatchedWallet, ticker string, recipient *model.Recipient) string {
+	if ticker == "" {
+		ticker = "TOKEN"
+	}
+
+	explorer := recipient.GetExplorer()
+
+	var action, counterpartyTitle string
+	switch movement.Kind {
+	case walletMovementSell:
+		action, counterpartyTitle = "Sold 🔻", "Sold to"
+	case walletMovementBuy:
+		action, counterpartyTitle = "Bought 🟢", "Bought from"
+	case walletMovementOut:
+		action, counterpartyTitle = "Sent ➡️", "To"
+	case walletMovementIn:
+		action, counterpartyTitle = "Received ⬅️", "From"
+	default:
+		action = "Opened a token account 🆕"
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("<b>$%s WALLET MOVEMENT 👀</b>\n\n", ticker))
+	sb.WriteString(fmt.Sprintf("<b>👛 Wallet:</b> <a href=\"%s\">%s</a>\n", explorer.AccountURL(movement.Wallet), utils.EscapeHTML(watched.Label)))
+	sb.WriteString(fmt.Sprintf("<b>📌 Action:</b> %s\n", action))
+
+	if movement.Amount.Sign() > 0 {
+		sb.WriteString(fmt.Sprintf("<b>💰 Amount:</b> %s\n", utils.FormatTokenAmount(movement.Amount, movement.Decimals, ticker)))
+	}
+
+	if movement.QuoteAmount > 0 {
+		title := "Received"
+		if movement.Kind == walletMovementBuy {
+			title = "Spent"
+		}
+		sb.WriteString(fmt.Sprintf("<b>💵 %s:</b> %s\n", title, utils.FormatNumber(movement.QuoteAmount, movement.QuoteSymbol)))
+	}
+
+	if movement.AccountCreated && movement.Kind != walletMovementOpened {
+		sb.WriteString("<b>🆕 New token account</b>\n")
+	}
+
+	if movement.Counterparty != "" && counterpartyTitle != "" {
+		sb.WriteString(fmt.Sprintf("<b>🔁 %s:</b> <a href=\"%s\">%s</a>\n", counterpartyTitle, explorer.AccountURL(movement.Counterparty), utils.EscapeHTML(walletName(recipient, movement.Counterparty))))
+	}
+
+	sb.WriteString(fmt.Sprintf("<b>🔎 Transaction:</b> <a href=\"%s\">%s</a>", explorer.TxURL(movement.Signature), utils.ShortenAddress(movement.Signature)))
+
+	return sb.String()
+}
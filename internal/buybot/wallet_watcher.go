@@ -0,0 +1,369 @@
+package buybot
+
+import (
+	"consul-telegram-bot/internal/config"
+	"consul-telegram-bot/internal/logger"
+	"consul-telegram-bot/internal/model"
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"time"
+)
+
+const (
+	WalletWatchInterval  = 30 * time.Second
+	walletWatchPageSize  = 25
+	walletWatchMaxPages  = 4
+	walletWatchAttempts  = 5
+	walletWatchTimeout   = 2 * time.Minute
+	walletMovementBuy    = "buy"
+	walletMovementSell   = "sell"
+	walletMovementIn     = "transfer_in"
+	walletMovementOut    = "transfer_out"
+	walletMovementOpened = "account_created"
+)
+
+type WalletMovement struct {
+	Wallet         string
+	Mint           string
+	Kind           string
+	Amount         *big.Int
+	Decimals       int
+	QuoteSymbol    string
+	QuoteAmount    float64
+	Counterparty   string
+	AccountCreated bool
+	Signature      string
+	BlockTime      int64
+}
+
+type WalletWatcher struct {
+	client       *HeliusClient
+	logger       *logger.Logger
+	config       *config.Config
+	signalSender *SignalSender
+	cursors      map[string]string
+	failures     map[string]int
+}
+
+func NewWalletWatcher(client *HeliusClient, logger *logger.Logger, cfg *config.Config, signalSender *SignalSender) *WalletWatcher {
+	return &WalletWatcher{
+		client:       client,
+		logger:       logger,
+		config:       cfg,
+		signalSender: signalSender,
+		cursors:      make(map[string]string),
+		failures:     make(map[string]int),
+	}
+}
+
+func (w *WalletWatcher) Run() {
+	ticker := time.NewTicker(WalletWatchInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		w.checkWallets()
+	}
+}
+
+func (w *WalletWatcher) checkWallets() {
+	targets := w.collectTargets()
+	w.resetUnwatchedCursors(targets)
+
+	if len(targets) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), walletWatchTimeout)
+	defer cancel()
+
+	for address, mints := range targets {
+		if err := w.checkWallet(ctx, address, mints); err != nil {
+			w.logger.Warning("failed to check watched wallet %s: %s", address, err)
+		}
+	}
+}
+
+func (w *WalletWatcher) collectTargets() map[string]map[string]bool {
+	targets := make(map[string]map[string]bool)
+	recipients := make(map[int64]*model.Recipient)
+
+	for _, wallet := range model.FindAllWatchedWallets() {
+		recipient, ok := recipients[wallet.ChatID]
+		if !ok {
+			recipient, _ = model.FindRecipient(wallet.ChatID)
+			recipients[wallet.ChatID] = recipient
+		}
+
+		if recipient == nil || recipient.Receiving != 1 || recipient.GetThreadIdForSignalType(model.SignalTypeWallets) == 0 {
+			continue
+		}
+
+		mint := model.GetWithFallback(recipient.TokenAddress, w.config.TokenAddress)
+		if mint == "" {
+			continue
+		}
+
+		if targets[wallet.Address] == nil {
+			targets[wallet.Address] = make(map[string]bool)
+		}
+		targets[wallet.Address][mint] = true
+	}
+
+	return targets
+}
+
+func (w *WalletWatcher) checkWallet(ctx context.Context, address string, mints map[string]bool) error {
+	cursor := w.cursor(address)
+	if cursor == "" {
+		signatures, err := w.client.GetSignaturesForAddress(ctx, address, SignaturesOptions{Limit: 1})
+		if err != nil {
+			return err
+		}
+		if len(signatures) > 0 {
+			w.saveCursor(address, signatures[0].Signature)
+		}
+		return nil
+	}
+
+	signatures, err := w.fetchNewSignatures(ctx, address, cursor)
+	if err != nil {
+		return err
+	}
+
+	for i := len(signatures) - 1; i >= 0; i-- {
+		sig := signatures[i]
+
+		if sig.Err == nil {
+			tx, err := w.client.GetTransaction(ctx, sig.Signature)
+			if err != nil {
+				w.failures[sig.Signature]++
+				if w.failures[sig.Signature] < walletWatchAttempts {
+					return fmt.Errorf("failed to get transaction %s, retrying on next check: %w", sig.Signature, err)
+				}
+
+				w.logger.Warning("skipping transaction %s of watched wallet %s after %d failed attempts: %s", sig.Signature, address, walletWatchAttempts, err)
+			} else {
+				for mint := range mints {
+					if movement := analyzeWalletMovement(tx, address, mint, sig.Signature); movement != nil {
+						w.signalSender.SendWalletMovement(movement)
+					}
+				}
+			}
+		}
+
+		delete(w.failures, sig.Signature)
+		w.saveCursor(address, sig.Signature)
+	}
+
+	return nil
+}
+
+func (w *WalletWatcher) fetchNewSignatures(ctx context.Context, address string, until string) ([]SignatureInfo, error) {
+	var signatures []SignatureInfo
+	before := ""
+
+	for page := 0; page < walletWatchMaxPages; page++ {
+		signaturesPage, err := w.client.GetSignaturesForAddress(ctx, address, SignaturesOptions{
+			Limit:  walletWatchPageSize,
+			Before: before,
+			Until:  until,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		signatures = append(signatures, signaturesPage...)
+
+		if len(signaturesPage) < walletWatchPageSize {
+			return signatures, nil
+		}
+
+		before = signaturesPage[len(signaturesPage)-1].Signature
+	}
+
+	w.logger.Warning("more than %d new signatures for watched wallet %s, older signatures are skipped", walletWatchMaxPages*walletWatchPageSize, address)
+
+	return signatures, nil
+}
+
+// resetUnwatchedCursors drops the cursor of every wallet no chat watches any
+// more, so watching it again starts from its latest transaction.
+func (w *WalletWatcher) resetUnwatchedCursors(targets map[string]map[string]bool) {
+	for _, cursor := range model.FindAllWalletWatchCursors() {
+		if _, ok := targets[cursor.Address]; ok {
+			continue
+		}
+
+		delete(w.cursors, cursor.Address)
+		if err := cursor.Delete(); err != nil {
+			w.logger.Error("failed to delete wallet watch cursor for %s: %s", cursor.Address, err)
+		}
+	}
+}
+
+func (w *WalletWatcher) cursor(address string) string {
+	if signature, ok := w.cursors[address]; ok {
+		return signature
+	}
+
+	cursor, err := model.FindWalletWatchCursor(address)
+	if err != nil {
+		return ""
+	}
+
+	w.cursors[address] = cursor.LastSignature
+	return cursor.LastSignature
+}
+
+func (w *WalletWatcher) saveCursor(address string, signature string) {
+	w.cursors[address] = signature
+
+	cursor := &model.WalletWatchCursor{Address: address, LastSignature: signature}
+	if err := cursor.Save(); err != nil {
+		w.logger.Error("failed to save wallet watch cursor for %s: %s", address, err)
+	}
+}
+
+func analyzeWalletMovement(tx *TransactionResponse, wallet string, mint string, signature string) *WalletMovement {
+	if tx == nil || tx.Meta == nil || tx.Meta.Err != nil {
+		return nil
+	}
+
+	var walletBalance *ownerTokenBalance
+	var counterparty *ownerTokenBalance
+
+	balances := tokenBalancesByOwner(tx.Meta, mint)
+	for _, balance := range balances {
+		if balance.Owner == wallet {
+			walletBalance = balance
+			break
+		}
+	}
+
+	if walletBalance == nil {
+		return nil
+	}
+
+	delta := walletBalance.Delta()
+	created := tokenAccountCreated(tx.Meta, wallet, mint)
+
+	if delta.Sign() == 0 && !created {
+		return nil
+	}
+
+	for _, balance := range balances {
+		if balance.Owner == wallet || balance.Delta().Sign() == 0 || balance.Delta().Sign() == delta.Sign() {
+			continue
+		}
+		if counterparty == nil || new(big.Int).Abs(balance.Delta()).Cmp(new(big.Int).Abs(counterparty.Delta())) > 0 {
+			counterparty = balance
+		}
+	}
+
+	movement := &WalletMovement{
+		Wallet:         wallet,
+		Mint:           mint,
+		Amount:         new(big.Int).Abs(delta),
+		Decimals:       walletBalance.Decimals,
+		AccountCreated: created,
+		Signature:      signature,
+	}
+
+	if counterparty != nil {
+		movement.Counterparty = counterparty.Owner
+	}
+
+	if tx.BlockTime != nil {
+		movement.BlockTime = *tx.BlockTime
+	}
+
+	switch {
+	case delta.Sign() < 0:
+		movement.Kind = walletMovementOut
+		if quote := withoutRent(sellerQuoteProceeds(tx, wallet), tokenAccountRent(tx, wallet, mint)); quote.Amount > 0 {
+			movement.Kind = walletMovementSell
+			movement.QuoteSymbol, movement.QuoteAmount = quote.Symbol, quote.Amount
+		}
+	case delta.Sign() > 0:
+		movement.Kind = walletMovementIn
+		if quote := withoutRent(buyerQuoteSpend(tx, wallet), -tokenAccountRent(tx, wallet, mint)); quote.Amount > 0 {
+			movement.Kind = walletMovementBuy
+			movement.QuoteSymbol, movement.QuoteAmount = quote.Symbol, quote.Amount
+		}
+	default:
+		movement.Kind = walletMovementOpened
+	}
+
+	return movement
+}
+
+func tokenAccountCreated(meta *TransactionMeta, owner string, mint string) bool {
+	existed := make(map[int]bool)
+	for _, balance := range meta.PreTokenBalances {
+		if balance.Mint == mint && balance.Owner == owner {
+			existed[balance.AccountIndex] = true
+		}
+	}
+
+	for _, balance := range meta.PostTokenBalances {
+		if balance.Mint == mint && balance.Owner == owner && !existed[balance.AccountIndex] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// tokenAccountRent returns the lamports the owner paid into its token
+// accounts for mint created in the transaction, minus the rent refunded by
+// closed ones.
+func tokenAccountRent(tx *TransactionResponse, owner string, mint string) int64 {
+	pre := make(map[int]bool)
+	for _, balance := range tx.Meta.PreTokenBalances {
+		if balance.Mint == mint && balance.Owner == owner {
+			pre[balance.AccountIndex] = true
+		}
+	}
+
+	post := make(map[int]bool)
+	for _, balance := range tx.Meta.PostTokenBalances {
+		if balance.Mint == mint && balance.Owner == owner {
+			post[balance.AccountIndex] = true
+		}
+	}
+
+	lamportChange := func(index int) int64 {
+		if index >= len(tx.Meta.PreBalances) || index >= len(tx.Meta.PostBalances) {
+			return 0
+		}
+		return int64(tx.Meta.PostBalances[index]) - int64(tx.Meta.PreBalances[index])
+	}
+
+	var rent int64
+	for index := range post {
+		if !pre[index] {
+			rent += lamportChange(index)
+		}
+	}
+	for index := range pre {
+		if !post[index] {
+			rent += lamportChange(index)
+		}
+	}
+
+	return rent
+}
+
+// withoutRent adds lamports to a SOL quote, rounded to whole lamports, so the
+// caller can cancel out rent counted as spent or received.
+func withoutRent(quote *quoteSpend, lamports int64) *quoteSpend {
+	if quote.Stable || lamports == 0 {
+		return quote
+	}
+
+	quote.Amount = math.Round((quote.Amount+float64(lamports)/1e9)*1e9) / 1e9
+	quote.SolAmount = quote.Amount
+	return quote
+}
@@ -0,0 +1,248 @@
+package buybot
+
+import (
+	"consul-telegram-bot/internal/config"
+	"consul-telegram-bot/internal/logger"
+	"consul-telegram-bot/internal/model"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// newFakeWalletRPC serves newestFirst as the wallet's history, honoring the
+// before, until and limit options of getSignaturesForAddress, and returns null
+// for the missing transactions.
+func newFakeWalletRPC(t *testing.T, newestFirst []string, missing map[string]bool) (*HeliusClient, func() []string) {
+	t.Helper()
+
+	var fetched []string
+	var mu sync.Mutex
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			ID     int               `json:"id"`
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+
+		var result interface{}
+		switch request.Method {
+		case "getSignaturesForAddress":
+			var opts struct {
+				Limit  int    `json:"limit"`
+				Before string `json:"before"`
+				Until  string `json:"until"`
+			}
+			json.Unmarshal(request.Params[1], &opts)
+
+			page := []map[string]interface{}{}
+			started := opts.Before == ""
+			for _, signature := range newestFirst {
+				if signature == opts.Until || len(page) == opts.Limit {
+					break
+				}
+				if started {
+					page = append(page, map[string]interface{}{"signature": signature})
+				}
+				started = started || signature == opts.Before
+			}
+			result = page
+		case "getTransaction":
+			var signature string
+			json.Unmarshal(request.Params[0], &signature)
+
+			if missing[signature] {
+				break
+			}
+
+			mu.Lock()
+			fetched = append(fetched, signature)
+			mu.Unlock()
+
+			result = map[string]interface{}{
+				"transaction": map[string]interface{}{"message": map[string]interface{}{"accountKeys": []string{"Wallet"}}},
+				"meta":        map[string]interface{}{"preBalances": []uint64{1}, "postBalances": []uint64{1}},
+			}
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": request.ID, "result": result})
+	}))
+	t.Cleanup(server.Close)
+
+	return NewHeliusClient([]string{server.URL}, logger.New()), func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return fetched
+	}
+}
+
+func TestWalletWatcherPagesUntilCursor(t *testing.T) {
+	useTestStore(t)
+
+	var newestFirst []string
+	for i := 60; i > 0; i-- {
+		newestFirst = append(newestFirst, fmt.Sprintf("sig%d", i))
+	}
+	newestFirst = append(newestFirst, "cursor", "older")
+
+	client, fetched := newFakeWalletRPC(t, newestFirst, nil)
+	watcher := NewWalletWatcher(client, logger.New(), &config.Config{}, nil)
+	watcher.saveCursor("Wallet", "cursor")
+
+	if err := watcher.checkWallet(context.Background(), "Wallet", map[string]bool{"Mint": true}); err != nil {
+		t.Fatalf("checkWallet: %s", err)
+	}
+
+	got := fetched()
+	if len(got) != 60 {
+		t.Fatalf("fetched %d transactions, want 60", len(got))
+	}
+	if got[0] != "sig1" || got[59] != "sig60" {
+		t.Errorf("fetched %s..%s, want oldest first from sig1 to sig60", got[0], got[59])
+	}
+	if cursor := watcher.cursor("Wallet"); cursor != "sig60" {
+		t.Errorf("cursor = %s, want sig60", cursor)
+	}
+}
+
+func TestWalletWatcherSkipsTransactionAfterRepeatedFailures(t *testing.T) {
+	useTestStore(t)
+
+	client, fetched := newFakeWalletRPC(t, []string{"sig2", "broken", "cursor"}, map[string]bool{"broken": true})
+	watcher := NewWalletWatcher(client, logger.New(), &config.Config{}, nil)
+	watcher.saveCursor("Wallet", "cursor")
+
+	for attempt := 1; attempt < walletWatchAttempts; attempt++ {
+		if err := watcher.checkWallet(context.Background(), "Wallet", map[string]bool{"Mint": true}); err == nil {
+			t.Fatalf("attempt %d: err = nil for a transaction the RPC could not return", attempt)
+		}
+		if cursor := watcher.cursor("Wallet"); cursor != "cursor" {
+			t.Fatalf("attempt %d: cursor = %s, want it to stay at the failing transaction", attempt, cursor)
+		}
+	}
+
+	if err := watcher.checkWallet(context.Background(), "Wallet", map[string]bool{"Mint": true}); err != nil {
+		t.Fatalf("last attempt: %s", err)
+	}
+	if cursor := watcher.cursor("Wallet"); cursor != "sig2" {
+		t.Errorf("cursor = %s, want sig2 after skipping the broken transaction", cursor)
+	}
+	if got := fetched(); len(got) != 1 || got[0] != "sig2" {
+		t.Errorf("fetched %v, want [sig2]", got)
+	}
+}
+
+func TestWalletWatcherResetsUnwatchedCursors(t *testing.T) {
+	useTestStore(t)
+
+	watcher := NewWalletWatcher(nil, logger.New(), &config.Config{}, nil)
+	watcher.saveCursor("Watched", "sig1")
+	watcher.saveCursor("Unwatched", "sig2")
+
+	watcher.resetUnwatchedCursors(map[string]map[string]bool{"Watched": {"Mint": true}})
+
+	if _, err := model.FindWalletWatchCursor("Unwatched"); err == nil {
+		t.Error("cursor of an unwatched wallet was kept in the store")
+	}
+	if _, ok := watcher.cursors["Unwatched"]; ok {
+		t.Error("cursor of an unwatched wallet was kept in memory")
+	}
+	if cursor := watcher.cursor("Watched"); cursor != "sig1" {
+		t.Errorf("cursor of a watched wallet = %q, want sig1", cursor)
+	}
+}
+
+const (
+	fixtureWallet = "3Kh6b2yAxT3NVvRwHLbGnXhZ1mLxq7pVtRn8cWfJe4Dk"
+	fixtureFriend = "8pLqW2cVbN3xR7tYk5MzHs9dJf4GaEu6Qn1oKw2PiTy"
+	fixturePool   = "5Q544fKrFoe6tsEbD7S8EmxGTJYAKtTVhAW5Q5pge4j1"
+)
+
+func TestAnalyzeWalletMovement(t *testing.T) {
+	tests := []struct {
+		fixture          string
+		wantKind         string
+		wantAmount       int64
+		wantQuoteSymbol  string
+		wantQuoteAmount  float64
+		wantCounterparty string
+		wantCreated      bool
+	}{
+		{fixture: "transfer_out.json", wantKind: walletMovementOut, wantAmount: 1_000_000_000, wantCounterparty: fixtureFriend},
+		{fixture: "transfer_in_new_account.json", wantKind: walletMovementIn, wantAmount: 500_000_000, wantCounterparty: fixtureFriend, wantCreated: true},
+		{fixture: "buy_sol_new_account.json", wantKind: walletMovementBuy, wantAmount: 2_000_000_000, wantQuoteSymbol: "SOL", wantQuoteAmount: 1, wantCounterparty: fixturePool, wantCreated: true},
+		{fixture: "sell_usdc.json", wantKind: walletMovementSell, wantAmount: 2_000_000_000, wantQuoteSymbol: "USDC", wantQuoteAmount: 250, wantCounterparty: fixturePool},
+		{fixture: "sell_wsol.json", wantKind: walletMovementSell, wantAmount: 1_000_000_000, wantQuoteSymbol: "WSOL", wantQuoteAmount: 3, wantCounterparty: fixturePool},
+		{fixture: "sell_sol_close_account.json", wantKind: walletMovementSell, wantAmount: 5_000_000_000, wantQuoteSymbol: "SOL", wantQuoteAmount: 2, wantCounterparty: fixturePool},
+		{fixture: "account_opened.json", wantKind: walletMovementOpened, wantCreated: true},
+		{fixture: "failed.json"},
+		{fixture: "unrelated.json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			movement := analyzeWalletMovement(loadTransactionFixture(t, "wallets", tt.fixture), fixtureWallet, fixtureMint, "5fixture")
+
+			if tt.wantKind == "" {
+				if movement != nil {
+					t.Fatalf("movement = %+v, want nil", movement)
+				}
+				return
+			}
+			if movement == nil {
+				t.Fatalf("movement = nil, want %s", tt.wantKind)
+			}
+
+			if movement.Kind != tt.wantKind {
+				t.Errorf("kind = %q, want %q", movement.Kind, tt.wantKind)
+			}
+			if movement.Amount.Int64() != tt.wantAmount {
+				t.Errorf("amount = %s, want %d", movement.Amount, tt.wantAmount)
+			}
+			if movement.QuoteSymbol != tt.wantQuoteSymbol || movement.QuoteAmount != tt.wantQuoteAmount {
+				t.Errorf("quote = %v %s, want %v %s", movement.QuoteAmount, movement.QuoteSymbol, tt.wantQuoteAmount, tt.wantQuoteSymbol)
+			}
+			if movement.Counterparty != tt.wantCounterparty {
+				t.Errorf("counterparty = %q, want %q", movement.Counterparty, tt.wantCounterparty)
+			}
+			if movement.AccountCreated != tt.wantCreated {
+				t.Errorf("account created = %v, want %v", movement.AccountCreated, tt.wantCreated)
+			}
+			if movement.BlockTime != 1760000000 {
+				t.Errorf("block time = %d, want 1760000000", movement.BlockTime)
+			}
+		})
+	}
+}
+
+func TestTokenAccountCreated(t *testing.T) {
+	tests := []struct {
+		fixture string
+		owner   string
+		mint    string
+		want    bool
+	}{
+		{fixture: "transfer_in_new_account.json", owner: fixtureWallet, mint: fixtureMint, want: true},
+		{fixture: "transfer_in_new_account.json", owner: fixtureFriend, mint: fixtureMint},
+		{fixture: "transfer_out.json", owner: fixtureWallet, mint: fixtureMint},
+		{fixture: "transfer_out.json", owner: fixtureFriend, mint: fixtureMint, want: true},
+		{fixture: "account_opened.json", owner: fixtureWallet, mint: fixtureMint, want: true},
+		{fixture: "account_opened.json", owner: fixtureWallet, mint: USDCMint},
+		{fixture: "sell_sol_close_account.json", owner: fixtureWallet, mint: fixtureMint},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture+"/"+tt.owner[:4]+"/"+tt.mint[:4], func(t *testing.T) {
+			meta := loadTransactionFixture(t, "wallets", tt.fixture).Meta
+
+			if got := tokenAccountCreated(meta, tt.owner, tt.mint); got != tt.want {
+				t.Errorf("tokenAccountCreated = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
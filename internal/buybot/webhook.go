@@ -0,0 +1,190 @@
+package buybot
+
+import (
+	"consul-telegram-bot/internal/logger"
+	"consul-telegram-bot/internal/metrics"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+const (
+	WebhookPath        = "/webhooks/helius"
+	maxWebhookBodySize = 10 << 20
+)
+
+type EnhancedTransaction struct {
+	Signature        string                `json:"signature"`
+	Slot             uint64                `json:"slot"`
+	Timestamp        int64                 `json:"timestamp"`
+	Type             string                `json:"type"`
+	Source           string                `json:"source"`
+	Fee              uint64                `json:"fee"`
+	FeePayer         string                `json:"feePayer"`
+	TransactionError interface{}           `json:"transactionError"`
+	NativeTransfers  []EnhancedNativeMove  `json:"nativeTransfers"`
+	TokenTransfers   []EnhancedTokenMove   `json:"tokenTransfers"`
+	AccountData      []EnhancedAccountData `json:"accountData"`
+}
+
+type EnhancedNativeMove struct {
+	FromUserAccount string `json:"fromUserAccount"`
+	ToUserAccount   string `json:"toUserAccount"`
+	Amount          int64  `json:"amount"`
+}
+
+type EnhancedTokenMove struct {
+	FromUserAccount  string  `json:"fromUserAccount"`
+	ToUserAccount    string  `json:"toUserAccount"`
+	FromTokenAccount string  `json:"fromTokenAccount"`
+	ToTokenAccount   string  `json:"toTokenAccount"`
+	TokenAmount      float64 `json:"tokenAmount"`
+	Mint             string  `json:"mint"`
+}
+
+type EnhancedAccountData struct {
+	Account             string                       `json:"account"`
+	NativeBalanceChange int64                        `json:"nativeBalanceChange"`
+	TokenBalanceChanges []EnhancedTokenBalanceChange `json:"tokenBalanceChanges"`
+}
+
+type EnhancedTokenBalanceChange struct {
+	UserAccount    string `json:"userAccount"`
+	TokenAccount   string `json:"tokenAccount"`
+	Mint           string `json:"mint"`
+	RawTokenAmount struct {
+		TokenAmount string `json:"tokenAmount"`
+		Decimals    int    `json:"decimals"`
+	} `json:"rawTokenAmount"`
+}
+
+type WebhookHandler struct {
+	registry *Registry
+	logger   *logger.Logger
+	secret   string
+}
+
+func NewWebhookHandler(registry *Registry, logger *logger.Logger, secret string) *WebhookHandler {
+	return &WebhookHandler{
+		registry: registry,
+		logger:   logger,
+		secret:   secret,
+	}
+}
+
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !h.authorized(r) {
+		metrics.ErrorsTotal.WithLabelValues("buybot_webhook", "unauthorized").Inc()
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBodySize))
+	if err != nil {
+		metrics.ErrorsTotal.WithLabelValues("buybot_webhook", "read_body").Inc()
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+
+	var transactions []EnhancedTransaction
+	if err := json.Unmarshal(body, &transactions); err != nil {
+		h.logger.Error("failed to unmarshal webhook payload: %s", err)
+		metrics.ErrorsTotal.WithLabelValues("buybot_webhook", "unmarshal").Inc()
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	h.logger.Info("received webhook with %d transactions", len(transactions))
+	if err := h.registry.HandleWebhookTransactions(transactions); err != nil {
+		h.logger.Error("failed to handle webhook transactions, asking for redelivery: %s", err)
+		metrics.ErrorsTotal.WithLabelValues("buybot_webhook", "get_transaction").Inc()
+		http.Error(w, "failed to fetch transactions", http.StatusServiceUnavailable)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *WebhookHandler) authorized(r *http.Request) bool {
+	if h.secret == "" {
+		return false
+	}
+
+	header := r.Header.Get("Authorization")
+	return subtle.ConstantTimeCompare([]byte(header), []byte(h.secret)) == 1
+}
+
+// handleWebhookTransactions fetches every pushed transaction that touches the
+// token and analyzes it like a polled one, since enhanced payloads carry
+// neither signers nor pre-balances.
+func (m *Monitor) handleWebhookTransactions(transactions []EnhancedTransaction) error {
+	m.processMu.Lock()
+	defer m.processMu.Unlock()
+
+	var buyTransactions []*BuyTransaction
+	var sellTransactions []*SellTransaction
+	var firstErr error
+	processed := 0
+
+	for _, tx := range transactions {
+		if tx.Signature == "" || m.isProcessed(tx.Signature) {
+			continue
+		}
+
+		if tx.TransactionError != nil || !m.involvesToken(tx) {
+			continue
+		}
+
+		buyTx, sellTx, err := m.processTransaction(SignatureInfo{Signature: tx.Signature})
+		if err != nil {
+			m.logger.Error("failed to get webhook transaction %s: %s", tx.Signature, err)
+			if firstErr == nil {
+				firstErr = fmt.Errorf("failed to get transaction %s: %w", tx.Signature, err)
+			}
+			continue
+		}
+
+		if buyTx != nil {
+			buyTransactions = append(buyTransactions, buyTx)
+		}
+		if sellTx != nil {
+			sellTransactions = append(sellTransactions, sellTx)
+		}
+
+		m.markProcessed(tx.Signature)
+		processed++
+	}
+
+	if processed > 0 {
+		m.saveCursor()
+		m.dispatchBuys(buyTransactions)
+		m.dispatchSells(sellTransactions)
+	}
+
+	return firstErr
+}
+
+func (m *Monitor) involvesToken(tx EnhancedTransaction) bool {
+	for _, transfer := range tx.TokenTransfers {
+		if transfer.Mint == m.tokenAddress {
+			return true
+		}
+	}
+
+	for _, account := range tx.AccountData {
+		for _, change := range account.TokenBalanceChanges {
+			if change.Mint == m.tokenAddress {
+				return true
+			}
+		}
+	}
+
+	return false
+}
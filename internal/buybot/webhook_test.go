@@ -0,0 +1,168 @@
+package buybot
+
+import (
+	"consul-telegram-bot/internal/logger"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newFakeTransactionRPC(t *testing.T, transactions map[string]*TransactionResponse) *HeliusClient {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			ID     uint64            `json:"id"`
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+
+		var result interface{}
+		if request.Method == "getTransaction" {
+			var signature string
+			json.Unmarshal(request.Params[0], &signature)
+			if tx, ok := transactions[signature]; ok {
+				result = tx
+			}
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": request.ID, "result": result})
+	}))
+	t.Cleanup(server.Close)
+
+	return NewHeliusClient([]string{server.URL}, logger.New())
+}
+
+func enhancedTokenChangeFor(owner string, mint string, amount string) EnhancedTokenBalanceChange {
+	change := EnhancedTokenBalanceChange{UserAccount: owner, TokenAccount: owner + "-ata", Mint: mint}
+	change.RawTokenAmount.TokenAmount = amount
+	change.RawTokenAmount.Decimals = 6
+	return change
+}
+
+func pushedTransaction(signature string) EnhancedTransaction {
+	return EnhancedTransaction{
+		Signature:   signature,
+		AccountData: []EnhancedAccountData{{TokenBalanceChanges: []EnhancedTokenBalanceChange{enhancedTokenChangeFor("Trader", "Mint", "1000000")}}},
+	}
+}
+
+func TestHandleWebhookTransactionsMatchesPolling(t *testing.T) {
+	useTestStore(t)
+
+	transactions := map[string]*TransactionResponse{
+		"feepayer": {
+			Transaction: TransactionData{Message: MessageData{
+				Header:      MessageHeader{NumRequiredSignatures: 1},
+				AccountKeys: []string{"Trader", "Pool"},
+			}},
+			Meta: &TransactionMeta{
+				Fee:               5000,
+				PreBalances:       []uint64{2_000_005_000, 10_000_000_000},
+				PostBalances:      []uint64{1_000_000_000, 11_000_000_000},
+				PreTokenBalances:  []TokenBalance{tokenBalance(2, "Pool", "Mint", "5000000", 6), tokenBalance(3, "Trader", "Mint", "2000000", 6)},
+				PostTokenBalances: []TokenBalance{tokenBalance(2, "Pool", "Mint", "4000000", 6), tokenBalance(3, "Trader", "Mint", "3000000", 6)},
+			},
+		},
+		"relayed": {
+			Transaction: TransactionData{Message: MessageData{
+				Header:      MessageHeader{NumRequiredSignatures: 2},
+				AccountKeys: []string{"Relayer", "Trader", "Pool"},
+			}},
+			Meta: &TransactionMeta{
+				Fee:          5000,
+				PreBalances:  []uint64{1_000_000_000, 2_000_000_000, 10_000_000_000},
+				PostBalances: []uint64{999_995_000, 1_000_000_000, 11_000_000_000},
+				PreTokenBalances: []TokenBalance{
+					tokenBalance(3, "Pool", "Mint", "5000000", 6),
+				},
+				PostTokenBalances: []TokenBalance{
+					tokenBalance(3, "Pool", "Mint", "4000000", 6),
+					tokenBalance(4, "Trader", "Mint", "600000", 6),
+					tokenBalance(5, "Trader", "Mint", "400000", 6),
+				},
+			},
+		},
+		"sell": {
+			Transaction: TransactionData{Message: MessageData{
+				Header:      MessageHeader{NumRequiredSignatures: 1},
+				AccountKeys: []string{"Trader", "Pool"},
+			}},
+			Meta: &TransactionMeta{
+				Fee:               5000,
+				PreBalances:       []uint64{1_000_000_000, 10_000_000_000},
+				PostBalances:      []uint64{1_999_995_000, 9_000_000_000},
+				PreTokenBalances:  []TokenBalance{tokenBalance(2, "Trader", "Mint", "1000000", 6)},
+				PostTokenBalances: []TokenBalance{tokenBalance(2, "Trader", "Mint", "0", 6)},
+			},
+		},
+	}
+
+	monitor := NewMonitor(newFakeTransactionRPC(t, transactions), logger.New(), "Mint", 100)
+
+	buys := make(map[string]*BuyTransaction)
+	monitor.SetBuyBatchHandler(func(buyTxs []*BuyTransaction) {
+		for _, buyTx := range buyTxs {
+			buys[buyTx.Signature] = buyTx
+		}
+	})
+
+	err := monitor.handleWebhookTransactions([]EnhancedTransaction{
+		pushedTransaction("feepayer"),
+		pushedTransaction("relayed"),
+		pushedTransaction("sell"),
+	})
+	if err != nil {
+		t.Fatalf("handleWebhookTransactions: %s", err)
+	}
+
+	tests := []struct {
+		signature     string
+		wantAmount    int64
+		wantNewHolder bool
+		wantIncrease  float64
+	}{
+		{signature: "feepayer", wantAmount: 1000000, wantIncrease: 50},
+		{signature: "relayed", wantAmount: 1000000, wantNewHolder: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.signature, func(t *testing.T) {
+			buyTx := buys[tt.signature]
+			if buyTx == nil {
+				t.Fatal("got no buy")
+			}
+			if buyTx.Buyer != "Trader" {
+				t.Errorf("Buyer = %s, want Trader", buyTx.Buyer)
+			}
+			if buyTx.Amount.Int64() != tt.wantAmount {
+				t.Errorf("Amount = %s, want %d", buyTx.Amount, tt.wantAmount)
+			}
+			if buyTx.SolAmount != 1 {
+				t.Errorf("SolAmount = %v, want 1", buyTx.SolAmount)
+			}
+			if buyTx.NewHolder != tt.wantNewHolder || buyTx.PositionIncrease != tt.wantIncrease {
+				t.Errorf("NewHolder = %v, PositionIncrease = %v, want %v, %v", buyTx.NewHolder, buyTx.PositionIncrease, tt.wantNewHolder, tt.wantIncrease)
+			}
+		})
+	}
+
+	if buys["sell"] != nil {
+		t.Error("sell was reported as a buy")
+	}
+}
+
+func TestHandleWebhookTransactionsAsksForRedelivery(t *testing.T) {
+	useTestStore(t)
+
+	monitor := NewMonitor(newFakeTransactionRPC(t, nil), logger.New(), "Mint", 100)
+
+	if err := monitor.handleWebhookTransactions([]EnhancedTransaction{pushedTransaction("missing")}); err == nil {
+		t.Fatal("err = nil for a transaction the RPC could not return")
+	}
+	if monitor.isProcessed("missing") {
+		t.Error("missing transaction was marked processed")
+	}
+}
@@ -0,0 +1,145 @@
+package commands
+
+import (
+	"consul-telegram-bot/internal/metrics"
+	"consul-telegram-bot/internal/model"
+	"consul-telegram-bot/internal/router"
+	"consul-telegram-bot/internal/utils"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type statsPeriod struct {
+	Name     string
+	Duration time.Duration
+}
+
+var statsPeriods = []statsPeriod{
+	{Name: "1h", Duration: time.Hour},
+	{Name: "24h", Duration: 24 * time.Hour},
+	{Name: "7d", Duration: 7 * 24 * time.Hour},
+}
+
+func Volume(c *router.Context) {
+	tokenAddress := chatTokenAddress(c)
+	if tokenAddress == "" {
+		metrics.TelegramCommandsProcessed.WithLabelValues("volume", "error").Inc()
+		c.SendAnswer("🚧 Token address is not configured. Use /setup or set TOKEN_ADDRESS env.")
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString("📊 <b>Buy Volume</b>\n")
+
+	now := time.Now()
+	since := make([]time.Time, len(statsPeriods))
+	for i, period := range statsPeriods {
+		since[i] = now.Add(-period.Duration)
+	}
+
+	periodStats, err := model.GetBuyStatsSince(tokenAddress, since)
+	if err != nil {
+		metrics.TelegramCommandsProcessed.WithLabelValues("volume", "error").Inc()
+		metrics.ErrorsTotal.WithLabelValues("command", "volume_get_stats").Inc()
+		c.SendAnswer("🚧 Something went wrong. Please try again later.")
+		return
+	}
+
+	for i, period := range statsPeriods {
+		stats := periodStats[i]
+		sb.WriteString(fmt.Sprintf("\n<b>%s</b>\n", period.Name))
+		sb.WriteString(fmt.Sprintf("🛒 Buys: %d\n", stats.Count))
+		sb.WriteString(fmt.Sprintf("💵 Volume: %s", utils.FormatNumber(stats.SolVolume, "SOL")))
+		if stats.USDVolume > 0 {
+			sb.WriteString(" (" + utils.FormatUSD(stats.USDVolume) + ")")
+		}
+		sb.WriteString("\n")
+		sb.WriteString(fmt.Sprintf("🦊 Unique buyers: %d\n", stats.UniqueBuyers))
+	}
+
+	metrics.TelegramCommandsProcessed.WithLabelValues("volume", "success").Inc()
+	c.SendAnswer(strings.TrimSuffix(sb.String(), "\n"))
+}
+
+func TopBuyers(c *router.Context) {
+	tokenAddress := chatTokenAddress(c)
+	if tokenAddress == "" {
+		metrics.TelegramCommandsProcessed.WithLabelValues("topbuyers", "error").Inc()
+		c.SendAnswer("🚧 Token address is not configured. Use /setup or set TOKEN_ADDRESS env.")
+		return
+	}
+
+	period := statsPeriods[1]
+	if len(c.Args) > 0 {
+		found := false
+		for _, p := range statsPeriods {
+			if strings.EqualFold(p.Name, c.Args[0]) {
+				period = p
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			metrics.TelegramCommandsProcessed.WithLabelValues("topbuyers", "error").Inc()
+			c.SendAnswer("🚧 Usage: /topbuyers [1h|24h|7d]")
+			return
+		}
+	}
+
+	buyers, err := model.GetTopBuyers(tokenAddress, time.Now().Add(-period.Duration), 10)
+	if err != nil {
+		metrics.TelegramCommandsProcessed.WithLabelValues("topbuyers", "error").Inc()
+		metrics.ErrorsTotal.WithLabelValues("command", "topbuyers_get_buyers").Inc()
+		c.SendAnswer("🚧 Something went wrong. Please try again later.")
+		return
+	}
+
+	metrics.TelegramCommandsProcessed.WithLabelValues("topbuyers", "success").Inc()
+
+	if len(buyers) == 0 {
+		c.SendAnswer(fmt.Sprintf("🐳 <b>Top Buyers (%s)</b>\n\nNo buys in this period yet.", period.Name))
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("🐳 <b>Top Buyers (%s)</b>\n\n", period.Name))
+
+	medals := []string{"🥇", "🥈", "🥉"}
+	explorer := chatExplorer(c)
+
+	for i, buyer := range buyers {
+		var position string
+		if i < 3 {
+			position = medals[i]
+		} else {
+			position = fmt.Sprintf("%d.", i+1)
+		}
+
+		volume := utils.FormatNumber(buyer.SolAmount, "SOL")
+		if buyer.USDValue > 0 {
+			volume += " (" + utils.FormatUSD(buyer.USDValue) + ")"
+		}
+
+		sb.WriteString(fmt.Sprintf("%s <a href=\"%s\">%s</a> — %s in %d buys.\n", position, explorer.AccountURL(buyer.Buyer), utils.ShortenAddress(buyer.Buyer), volume, buyer.Count))
+	}
+
+	c.SendAnswer(strings.TrimSuffix(sb.String(), "\n"))
+}
+
+func chatExplorer(c *router.Context) *model.Explorer {
+	recipient, _ := model.FindRecipient(c.Message.Chat.ID)
+	if recipient != nil {
+		return recipient.GetExplorer()
+	}
+	return model.Explorers[0]
+}
+
+func chatTokenAddress(c *router.Context) string {
+	recipient, _ := model.FindRecipient(c.Message.Chat.ID)
+	if recipient != nil {
+		return model.GetWithFallback(recipient.TokenAddress, c.Config.TokenAddress)
+	}
+	return c.Config.TokenAddress
+}
@@ -0,0 +1,206 @@
+package commands
+
+import (
+	"consul-telegram-bot/internal/buybot"
+	"consul-telegram-bot/internal/metrics"
+	"consul-telegram-bot/internal/middlewares"
+	"consul-telegram-bot/internal/model"
+	"consul-telegram-bot/internal/router"
+	"consul-telegram-bot/internal/utils"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+func SetBuyTemplate(c *router.Context) {
+	middlewares.Manager(setBuyTemplateHandler, c.Config.ManagerId)(c)
+}
+
+func PreviewBuy(c *router.Context) {
+	middlewares.Manager(previewBuyHandler, c.Config.ManagerId)(c)
+}
+
+func AddBuyButton(c *router.Context) {
+	middlewares.Manager(addBuyButtonHandler, c.Config.ManagerId)(c)
+}
+
+func BuyButtons(c *router.Context) {
+	middlewares.Manager(buyButtonsHandler, c.Config.ManagerId)(c)
+}
+
+func RemoveBuyButton(c *router.Context) {
+	middlewares.Manager(removeBuyButtonHandler, c.Config.ManagerId)(c)
+}
+
+func setBuyTemplateHandler(c *router.Context) {
+	recipient, err := model.FindRecipient(c.Message.Chat.ID)
+	if err != nil {
+		metrics.TelegramCommandsProcessed.WithLabelValues("set_buy_template", "error").Inc()
+		c.SendAnswer("🚧 Please run /start first.")
+		return
+	}
+
+	template := commandPayload(c)
+	if template == "" {
+		metrics.TelegramCommandsProcessed.WithLabelValues("set_buy_template", "error").Inc()
+		c.SendAnswer(
+			"🚧 Usage: /set_buy_template <template>, or <code>reset</code> for the default layout.\n\n" +
+				"<b>Placeholders:</b>\n<code>{" + strings.Join(buybot.BuyTemplatePlaceholders, "}</code>, <code>{") + "}</code>\n\n" +
+				"<b>Example:</b>\n<code>&lt;b&gt;{ticker} buy {emoji}&lt;/b&gt;\n{amount} for {spent} {usd}\nBuyer: {buyer_short}\n{tx_link}</code>",
+		)
+		return
+	}
+
+	if strings.EqualFold(template, "reset") {
+		recipient.BuyTemplate = ""
+	} else {
+		if err := buybot.ValidateBuyTemplate(template); err != nil {
+			metrics.TelegramCommandsProcessed.WithLabelValues("set_buy_template", "error").Inc()
+			c.SendAnswer("⚠️ Invalid template: " + utils.EscapeHTML(err.Error()) + ".")
+			return
+		}
+		recipient.BuyTemplate = template
+	}
+
+	if err := recipient.Write(); err != nil {
+		metrics.TelegramCommandsProcessed.WithLabelValues("set_buy_template", "error").Inc()
+		c.SendAnswer("🚧 Failed to save.")
+		return
+	}
+
+	metrics.TelegramCommandsProcessed.WithLabelValues("set_buy_template", "success").Inc()
+	c.SendAnswer("✅ Buy template updated. Preview with a sample buy:\n\n" + formatBuyPreview(c, recipient))
+}
+
+func previewBuyHandler(c *router.Context) {
+	recipient, err := model.FindRecipient(c.Message.Chat.ID)
+	if err != nil {
+		metrics.TelegramCommandsProcessed.WithLabelValues("preview_buy", "error").Inc()
+		c.SendAnswer("🚧 Please run /start first.")
+		return
+	}
+
+	metrics.TelegramCommandsProcessed.WithLabelValues("preview_buy", "success").Inc()
+	c.SendAnswer(formatBuyPreview(c, recipient))
+}
+
+func addBuyButtonHandler(c *router.Context) {
+	recipient, err := model.FindRecipient(c.Message.Chat.ID)
+	if err != nil {
+		metrics.TelegramCommandsProcessed.WithLabelValues("add_buy_button", "error").Inc()
+		c.SendAnswer("🚧 Please run /start first.")
+		return
+	}
+
+	text, buttonURL, found := strings.Cut(c.GetArgString(), "|")
+	if !found {
+		metrics.TelegramCommandsProcessed.WithLabelValues("add_buy_button", "error").Inc()
+		c.SendAnswer("🚧 Usage: /add_buy_button <text> | <url>\n\nExample: <code>/add_buy_button Buyer on Solscan | https://solscan.io/account/{buyer}</code>")
+		return
+	}
+
+	button := model.BuyButton{
+		Text: strings.TrimSpace(text),
+		URL:  strings.TrimSpace(buttonURL),
+	}
+
+	if err := buybot.ValidateBuyButton(button); err != nil {
+		metrics.TelegramCommandsProcessed.WithLabelValues("add_buy_button", "error").Inc()
+		c.SendAnswer("⚠️ Invalid button: " + utils.EscapeHTML(err.Error()) + ".")
+		return
+	}
+
+	if !recipient.AddBuyButton(button) {
+		metrics.TelegramCommandsProcessed.WithLabelValues("add_buy_button", "error").Inc()
+		c.SendAnswer(fmt.Sprintf("🚧 This chat already has %d buttons, the maximum.", model.MaxBuyButtons))
+		return
+	}
+
+	if err := recipient.Write(); err != nil {
+		metrics.TelegramCommandsProcessed.WithLabelValues("add_buy_button", "error").Inc()
+		c.SendAnswer("🚧 Failed to save.")
+		return
+	}
+
+	metrics.TelegramCommandsProcessed.WithLabelValues("add_buy_button", "success").Inc()
+	c.SendAnswer(fmt.Sprintf("✅ Button added. Buy alerts in this chat now have %d custom button(s).", len(recipient.BuyButtons)))
+}
+
+func buyButtonsHandler(c *router.Context) {
+	recipient, err := model.FindRecipient(c.Message.Chat.ID)
+	if err != nil {
+		metrics.TelegramCommandsProcessed.WithLabelValues("buy_buttons", "error").Inc()
+		c.SendAnswer("🚧 Please run /start first.")
+		return
+	}
+
+	metrics.TelegramCommandsProcessed.WithLabelValues("buy_buttons", "success").Inc()
+
+	if len(recipient.BuyButtons) == 0 {
+		c.SendAnswer("🔘 No custom buttons yet, buy alerts link to Dexscreener and Axiom.\n\nUse /add_buy_button &lt;text&gt; | &lt;url&gt; to add one.")
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("🔘 <b>Buy Alert Buttons</b> (%d/%d)\n\n", len(recipient.BuyButtons), model.MaxBuyButtons))
+
+	for i, button := range recipient.BuyButtons {
+		sb.WriteString(fmt.Sprintf("%d. %s — <code>%s</code>\n", i+1, utils.EscapeHTML(button.Text), utils.EscapeHTML(button.URL)))
+	}
+
+	sb.WriteString("\n<i>Use /remove_buy_button &lt;number&gt; to remove one.</i>")
+
+	c.SendAnswer(sb.String())
+}
+
+func removeBuyButtonHandler(c *router.Context) {
+	recipient, err := model.FindRecipient(c.Message.Chat.ID)
+	if err != nil {
+		metrics.TelegramCommandsProcessed.WithLabelValues("remove_buy_button", "error").Inc()
+		c.SendAnswer("🚧 Please run /start first.")
+		return
+	}
+
+	if len(c.Args) == 0 {
+		metrics.TelegramCommandsProcessed.WithLabelValues("remove_buy_button", "error").Inc()
+		c.SendAnswer("🚧 Usage: /remove_buy_button <number>. Use /buy_buttons to see the list.")
+		return
+	}
+
+	number, err := strconv.Atoi(c.Args[0])
+	if err != nil || !recipient.RemoveBuyButton(number-1) {
+		metrics.TelegramCommandsProcessed.WithLabelValues("remove_buy_button", "error").Inc()
+		c.SendAnswer("🚧 Button not found. Use /buy_buttons to see the list.")
+		return
+	}
+
+	if err := recipient.Write(); err != nil {
+		metrics.TelegramCommandsProcessed.WithLabelValues("remove_buy_button", "error").Inc()
+		c.SendAnswer("🚧 Failed to save.")
+		return
+	}
+
+	metrics.TelegramCommandsProcessed.WithLabelValues("remove_buy_button", "success").Inc()
+	c.SendAnswer(fmt.Sprintf("✅ Button #%d removed.", number))
+}
+
+func formatBuyPreview(c *router.Context, recipient *model.Recipient) string {
+	message, buttons := buybot.PreviewSampleBuy(c.Config, c.Logger, recipient)
+
+	if len(buttons) > 0 {
+		message += "\n"
+		for _, button := range buttons {
+			message += fmt.Sprintf("\n🔘 <a href=\"%s\">%s</a>", utils.EscapeHTML(button.URL), utils.EscapeHTML(button.Text))
+		}
+	}
+
+	return message
+}
+
+func commandPayload(c *router.Context) string {
+	index := strings.IndexAny(c.Message.Text, " \n")
+	if index == -1 {
+		return ""
+	}
+	return strings.TrimSpace(c.Message.Text[index+1:])
+}
@@ -25,7 +25,9 @@ func CA(c *router.Context) {
 
 	metrics.TelegramCommandsProcessed.WithLabelValues("ca", "success").Inc()
 
-	message := "<code>" + tokenAddress + "</code>"
+	explorer := chatExplorer(c)
+
+	message := "<code>" + tokenAddress + "</code>\n\nView on <a href=\"" + explorer.TokenURL(tokenAddress) + "\">" + explorer.Name + "</a>."
 
 	c.SendAnswer(message)
 }
@@ -1,11 +1,31 @@
 package commands
 
 import (
+	"consul-telegram-bot/internal/dexscreener"
 	"consul-telegram-bot/internal/metrics"
 	"consul-telegram-bot/internal/model"
 	"consul-telegram-bot/internal/router"
+	"consul-telegram-bot/internal/utils"
+	"fmt"
+	"strings"
+	"time"
 )
 
+const (
+	chartChainID  = "solana"
+	chartCacheTTL = time.Minute
+)
+
+var chartPairs = dexscreener.NewPairCache(chartCacheTTL)
+
+var chartTimeframes = []struct {
+	Key   string
+	Frame string
+}{
+	{Key: "h1", Frame: "1h"},
+	{Key: "h24", Frame: "24h"},
+}
+
 func Chart(c *router.Context) {
 	chat := c.Message.Chat
 	recipient, _ := model.FindRecipient(chat.ID)
@@ -17,6 +37,16 @@ func Chart(c *router.Context) {
 		dexURL = c.Config.DexURL
 	}
 
+	pair, err := findChartPair(c, dexURL, chatTokenAddress(c))
+	if err != nil {
+		c.Logger.Error("failed to get chart pair: %s", err)
+		metrics.ErrorsTotal.WithLabelValues("command", "chart_get_pair").Inc()
+	}
+
+	if pair != nil && dexURL == "" {
+		dexURL = pair.URL
+	}
+
 	if dexURL == "" {
 		metrics.TelegramCommandsProcessed.WithLabelValues("chart", "error").Inc()
 		c.SendAnswer("🚧 Dexscreener URL is not configured. Use /setup or set DEX_URL env.")
@@ -26,6 +56,82 @@ func Chart(c *router.Context) {
 	metrics.TelegramCommandsProcessed.WithLabelValues("chart", "success").Inc()
 
 	message := "View on <a href=\"" + dexURL + "\">Dexscreener</a> for more details."
+	if pair != nil {
+		message = formatChartPair(pair) + "\n" + message
+	}
 
 	c.SendAnswer(message)
 }
+
+func findChartPair(c *router.Context, dexURL string, tokenAddress string) (*dexscreener.Pair, error) {
+	client := dexscreener.NewClient(c.Config.DexscreenerAPIURL)
+
+	if chainID, pairAddress, ok := dexscreener.ParsePairURL(dexURL); ok {
+		pair, err := chartPairs.Get(chainID+"/"+pairAddress, func() (*dexscreener.Pair, error) {
+			return client.GetPair(chainID, pairAddress)
+		})
+		if err == nil || tokenAddress == "" {
+			return pair, err
+		}
+	}
+
+	if tokenAddress == "" {
+		return nil, nil
+	}
+
+	return chartPairs.Get(chartChainID+"/"+tokenAddress, func() (*dexscreener.Pair, error) {
+		pairs, err := client.GetTokenPairs(chartChainID, tokenAddress)
+		if err != nil {
+			return nil, err
+		}
+
+		pair := dexscreener.MostLiquidPair(pairs, tokenAddress)
+		if pair == nil {
+			return nil, fmt.Errorf("no priced pairs found for %s", tokenAddress)
+		}
+		return pair, nil
+	})
+}
+
+func formatChartPair(pair *dexscreener.Pair) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("📈 <b>%s/%s</b>", utils.EscapeHTML(pair.BaseToken.Symbol), utils.EscapeHTML(pair.QuoteToken.Symbol)))
+	if pair.DexID != "" {
+		sb.WriteString(" on " + utils.EscapeHTML(pair.DexID))
+	}
+	sb.WriteString("\n\n")
+
+	sb.WriteString(fmt.Sprintf("💵 <b>Price:</b> %s\n", utils.FormatPrice(pair.PriceUSD())))
+	for _, timeframe := range chartTimeframes {
+		change, ok := pair.PriceChange[timeframe.Key]
+		if !ok {
+			continue
+		}
+
+		icon := "🟢"
+		if change < 0 {
+			icon = "🔴"
+		}
+		sb.WriteString(fmt.Sprintf("%s <b>%s:</b> %s\n", icon, utils.FormatTimeframe(timeframe.Frame), utils.FormatChange(change)))
+	}
+
+	if volume, ok := pair.Volume["h24"]; ok {
+		sb.WriteString(fmt.Sprintf("📊 <b>Volume (24h):</b> %s\n", utils.FormatUSD(volume)))
+	}
+	if liquidity := pair.LiquidityUSD(); liquidity > 0 {
+		sb.WriteString(fmt.Sprintf("💧 <b>Liquidity:</b> %s\n", utils.FormatUSD(liquidity)))
+	}
+	if pair.Fdv > 0 {
+		sb.WriteString(fmt.Sprintf("🏦 <b>FDV:</b> %s\n", utils.FormatUSD(pair.Fdv)))
+	}
+	for _, timeframe := range chartTimeframes {
+		txns, ok := pair.Txns[timeframe.Key]
+		if !ok {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("🔄 <b>Txns (%s):</b> %d buys / %d sells\n", timeframe.Frame, txns.Buys, txns.Sells))
+	}
+
+	return sb.String()
+}
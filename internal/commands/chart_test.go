@@ -0,0 +1,37 @@
+package commands
+
+import (
+	"consul-telegram-bot/internal/dexscreener"
+	"strings"
+	"testing"
+)
+
+func TestFormatChartPair(t *testing.T) {
+	pair := &dexscreener.Pair{
+		DexID:       "raydium",
+		BaseToken:   dexscreener.Token{Symbol: "TOKEN"},
+		QuoteToken:  dexscreener.Token{Symbol: "SOL"},
+		PriceUsd:    "0.5",
+		PriceChange: map[string]float64{"h1": -2.5, "h24": 12.25},
+		Txns:        map[string]dexscreener.TxnCount{"h24": {Buys: 10, Sells: 4}},
+	}
+
+	got := formatChartPair(pair)
+
+	for _, want := range []string{
+		"📈 <b>TOKEN/SOL</b> on raydium",
+		"🔴 <b>1 hour:</b> -2.50%",
+		"🟢 <b>24 hours:</b> 12.25%",
+		"🔄 <b>Txns (24h):</b> 10 buys / 4 sells",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("formatChartPair() is missing %q:\n%s", want, got)
+		}
+	}
+
+	for _, unwanted := range []string{"Liquidity", "FDV", "Txns (1h)"} {
+		if strings.Contains(got, unwanted) {
+			t.Errorf("formatChartPair() shows %q without data:\n%s", unwanted, got)
+		}
+	}
+}
@@ -26,9 +26,25 @@ func clearHandler(c *router.Context) {
 	recipient.TokenAddress = ""
 	recipient.DexURL = ""
 	recipient.AxiomURL = ""
+	recipient.MinBuySol = 0
+	recipient.MinBuyUSD = 0
+	recipient.BuyTiers = nil
+	recipient.BuyDigestMinutes = 0
+	recipient.MinSellSol = 0
+	recipient.GifFileIDs = nil
+	recipient.NewHoldersOnly = false
+	recipient.Venues = nil
+	recipient.GateMinBalance = 0
+	recipient.GateAction = ""
+	recipient.BuyTemplate = ""
+	recipient.BuyButtons = nil
+	recipient.Explorer = ""
 	recipient.ThreadId = 0
 	recipient.BuysThreadId = 0
+	recipient.SellsThreadId = 0
 	recipient.RetransmitThreadId = 0
+	recipient.SnipersThreadId = 0
+	recipient.WalletsThreadId = 0
 
 	err = recipient.Write()
 	if err != nil {
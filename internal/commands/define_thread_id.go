@@ -21,7 +21,7 @@ func defineThreadIdHandler(c *router.Context) {
 
 	if len(c.Args) == 0 {
 		metrics.TelegramCommandsProcessed.WithLabelValues("define_thread_id", "error").Inc()
-		c.SendAnswer("🚧 Please specify signal type: buys, retransmit.")
+		c.SendAnswer("🚧 Please specify signal type: buys, sells, snipers, wallets, retransmit.")
 		return
 	}
 
@@ -29,7 +29,7 @@ func defineThreadIdHandler(c *router.Context) {
 	signalType, ok := model.ParseSignalType(signalTypeStr)
 	if !ok {
 		metrics.TelegramCommandsProcessed.WithLabelValues("define_thread_id", "error").Inc()
-		c.SendAnswer("🚧 Invalid signal type. Available types: buys, retransmit.")
+		c.SendAnswer("🚧 Invalid signal type. Available types: buys, sells, snipers, wallets, retransmit.")
 		return
 	}
 
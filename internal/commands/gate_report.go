@@ -0,0 +1,66 @@
+package commands
+
+import (
+	"consul-telegram-bot/internal/metrics"
+	"consul-telegram-bot/internal/middlewares"
+	"consul-telegram-bot/internal/model"
+	"consul-telegram-bot/internal/router"
+	"consul-telegram-bot/internal/utils"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+func GateReport(c *router.Context) {
+	middlewares.Manager(gateReportHandler, c.Config.ManagerId)(c)
+}
+
+func gateReportHandler(c *router.Context) {
+	recipient, err := model.FindRecipient(c.Message.Chat.ID)
+	if err != nil {
+		metrics.TelegramCommandsProcessed.WithLabelValues("gate_report", "error").Inc()
+		c.SendAnswer("🚧 Please run /start first.")
+		return
+	}
+
+	if recipient.GateMinBalance <= 0 {
+		metrics.TelegramCommandsProcessed.WithLabelValues("gate_report", "error").Inc()
+		c.SendAnswer("🚧 Token gating is off. Enable it with <code>/set gate_min 100000</code>.")
+		return
+	}
+
+	var passing, failing []string
+	for _, member := range model.FindGateMembers(recipient.Id) {
+		line := utils.EscapeHTML(member.DisplayName())
+		if balance, ok := new(big.Int).SetString(member.Balance, 10); ok {
+			line += " — " + utils.FormatTokenAmount(balance, member.Decimals, "")
+		}
+		if member.Restricted {
+			line += " 🔇"
+		}
+
+		if member.Passing {
+			passing = append(passing, line)
+		} else {
+			failing = append(failing, line)
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("🛂 <b>Gate Report</b>\n\n<b>Minimum:</b> %s tokens\n\n", utils.FormatNumber(recipient.GateMinBalance, "")))
+
+	sb.WriteString(fmt.Sprintf("✅ <b>Passing (%d)</b>\n", len(passing)))
+	for _, line := range passing {
+		sb.WriteString(line + "\n")
+	}
+
+	sb.WriteString(fmt.Sprintf("\n⚠️ <b>Failing (%d)</b>\n", len(failing)))
+	for _, line := range failing {
+		sb.WriteString(line + "\n")
+	}
+
+	sb.WriteString("\n<i>Members are tracked once they join or post in the chat.</i>")
+
+	metrics.TelegramCommandsProcessed.WithLabelValues("gate_report", "success").Inc()
+	c.SendAnswer(sb.String())
+}
@@ -0,0 +1,161 @@
+package commands
+
+import (
+	"consul-telegram-bot/internal/metrics"
+	"consul-telegram-bot/internal/middlewares"
+	"consul-telegram-bot/internal/model"
+	"consul-telegram-bot/internal/router"
+	"fmt"
+	"strconv"
+	"strings"
+
+	telebot "gopkg.in/telebot.v3"
+)
+
+func AddGif(c *router.Context) {
+	middlewares.Manager(addGifHandler, c.Config.ManagerId)(c)
+}
+
+func Gifs(c *router.Context) {
+	middlewares.Manager(gifsHandler, c.Config.ManagerId)(c)
+}
+
+func RemoveGif(c *router.Context) {
+	middlewares.Manager(removeGifHandler, c.Config.ManagerId)(c)
+}
+
+func ResetGifs(c *router.Context) {
+	middlewares.Manager(resetGifsHandler, c.Config.ManagerId)(c)
+}
+
+func addGifHandler(c *router.Context) {
+	recipient, err := model.FindRecipient(c.Message.Chat.ID)
+	if err != nil {
+		metrics.TelegramCommandsProcessed.WithLabelValues("add_gif", "error").Inc()
+		c.SendAnswer("🚧 Please run /start first.")
+		return
+	}
+
+	fileID := animationFileID(c.Message.ReplyTo)
+	if fileID == "" {
+		metrics.TelegramCommandsProcessed.WithLabelValues("add_gif", "error").Inc()
+		c.SendAnswer("⚠️ Reply to a GIF with /add_gif to add it to buy alerts.")
+		return
+	}
+
+	if !recipient.AddGif(fileID) {
+		metrics.TelegramCommandsProcessed.WithLabelValues("add_gif", "error").Inc()
+		c.SendAnswer(fmt.Sprintf("🚧 This GIF is already added or the pool is full (%d max).", model.MaxGifs))
+		return
+	}
+
+	if err := recipient.Write(); err != nil {
+		metrics.TelegramCommandsProcessed.WithLabelValues("add_gif", "error").Inc()
+		c.SendAnswer("🚧 Failed to save.")
+		return
+	}
+
+	metrics.TelegramCommandsProcessed.WithLabelValues("add_gif", "success").Inc()
+	c.SendAnswer(fmt.Sprintf("✅ GIF added. This chat now has %d custom GIF(s).", len(recipient.GifFileIDs)))
+}
+
+func gifsHandler(c *router.Context) {
+	recipient, err := model.FindRecipient(c.Message.Chat.ID)
+	if err != nil {
+		metrics.TelegramCommandsProcessed.WithLabelValues("gifs", "error").Inc()
+		c.SendAnswer("🚧 Please run /start first.")
+		return
+	}
+
+	metrics.TelegramCommandsProcessed.WithLabelValues("gifs", "success").Inc()
+
+	if len(recipient.GifFileIDs) == 0 {
+		c.SendAnswer("🎞 No custom GIFs yet, buy alerts use the default set.\n\nReply to a GIF with /add_gif to add one.")
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("🎞 <b>Custom GIFs</b> (%d/%d)\n\n", len(recipient.GifFileIDs), model.MaxGifs))
+
+	for i, fileID := range recipient.GifFileIDs {
+		sb.WriteString(fmt.Sprintf("%d. <code>%s</code>\n", i+1, shortenFileID(fileID)))
+	}
+
+	sb.WriteString("\n<i>Use /remove_gif &lt;number&gt; to remove one or /reset_gifs to restore the defaults.</i>")
+
+	c.SendAnswer(sb.String())
+}
+
+func removeGifHandler(c *router.Context) {
+	recipient, err := model.FindRecipient(c.Message.Chat.ID)
+	if err != nil {
+		metrics.TelegramCommandsProcessed.WithLabelValues("remove_gif", "error").Inc()
+		c.SendAnswer("🚧 Please run /start first.")
+		return
+	}
+
+	if len(c.Args) == 0 {
+		metrics.TelegramCommandsProcessed.WithLabelValues("remove_gif", "error").Inc()
+		c.SendAnswer("🚧 Usage: /remove_gif <number>. Use /gifs to see the list.")
+		return
+	}
+
+	number, err := strconv.Atoi(c.Args[0])
+	if err != nil || !recipient.RemoveGif(number-1) {
+		metrics.TelegramCommandsProcessed.WithLabelValues("remove_gif", "error").Inc()
+		c.SendAnswer("🚧 GIF not found. Use /gifs to see the list.")
+		return
+	}
+
+	if err := recipient.Write(); err != nil {
+		metrics.TelegramCommandsProcessed.WithLabelValues("remove_gif", "error").Inc()
+		c.SendAnswer("🚧 Failed to save.")
+		return
+	}
+
+	metrics.TelegramCommandsProcessed.WithLabelValues("remove_gif", "success").Inc()
+	c.SendAnswer(fmt.Sprintf("✅ GIF #%d removed.", number))
+}
+
+func resetGifsHandler(c *router.Context) {
+	recipient, err := model.FindRecipient(c.Message.Chat.ID)
+	if err != nil {
+		metrics.TelegramCommandsProcessed.WithLabelValues("reset_gifs", "error").Inc()
+		c.SendAnswer("🚧 Please run /start first.")
+		return
+	}
+
+	recipient.ResetGifs()
+
+	if err := recipient.Write(); err != nil {
+		metrics.TelegramCommandsProcessed.WithLabelValues("reset_gifs", "error").Inc()
+		c.SendAnswer("🚧 Failed to save.")
+		return
+	}
+
+	metrics.TelegramCommandsProcessed.WithLabelValues("reset_gifs", "success").Inc()
+	c.SendAnswer("✅ Custom GIFs removed, buy alerts use the default set again.")
+}
+
+func animationFileID(m *telebot.Message) string {
+	if m == nil {
+		return ""
+	}
+
+	if m.Animation != nil {
+		return m.Animation.FileID
+	}
+
+	if m.Document != nil && (m.Document.MIME == "image/gif" || m.Document.MIME == "video/mp4") {
+		return m.Document.FileID
+	}
+
+	return ""
+}
+
+func shortenFileID(fileID string) string {
+	if len(fileID) <= 16 {
+		return fileID
+	}
+	return fileID[:8] + "..." + fileID[len(fileID)-6:]
+}
@@ -15,10 +15,17 @@ func Help(c *router.Context) {
 		"<b>Ecosystem:</b>\n" +
 		"/website - Get website link.\n" +
 		"/ca - Get contract address.\n" +
-		"/chart - View chart on Dexscreener.\n\n" +
+		"/chart - Price, volume and liquidity.\n" +
+		"/volume - Buy volume for 1h, 24h and 7d.\n" +
+		"/topbuyers - Largest buyers (1h, 24h or 7d).\n" +
+		"/holders - Supply, holder count and largest wallets.\n\n" +
 		"<b>Community:</b>\n" +
 		"/up - Give a point (reply to a message).\n" +
 		"/leaderboard - View top contributors.\n\n" +
+		"<b>Wallets:</b>\n" +
+		"/link_wallet - Link a Solana wallet (private chat).\n" +
+		"/wallets - List your linked wallets (private chat).\n" +
+		"/unlink_wallet - Unlink a wallet (private chat).\n\n" +
 		"<b>AI:</b>\n" +
 		"/summary - Get AI summary of recent messages.\n" +
 		"/consul - Get AI response to a question."
@@ -30,7 +37,24 @@ func Help(c *router.Context) {
 			"/clear - Clear all settings.\n" +
 			"/define_thread_id - Set thread.\n" +
 			"/retransmit - Broadcast message.\n" +
-			"/set_llm_context - Set LLM context."
+			"/set_llm_context - Set LLM context.\n" +
+			"/add_gif - Add a buy GIF (reply to a GIF).\n" +
+			"/gifs - List buy GIFs.\n" +
+			"/remove_gif - Remove a buy GIF.\n" +
+			"/reset_gifs - Restore default buy GIFs.\n" +
+			"/set_buy_template - Set the buy alert template.\n" +
+			"/preview_buy - Preview a buy alert.\n" +
+			"/add_buy_button - Add a buy alert button.\n" +
+			"/buy_buttons - List buy alert buttons.\n" +
+			"/remove_buy_button - Remove a buy alert button.\n" +
+			"/label_wallet - Label a wallet in alerts (.txt for bulk).\n" +
+			"/ignore_wallet - Skip a wallet's buys and sells (.txt for bulk).\n" +
+			"/unlabel_wallet - Remove a wallet label.\n" +
+			"/wallet_labels - List labeled and ignored wallets.\n" +
+			"/watch_wallet - Post token movements of a wallet.\n" +
+			"/unwatch_wallet - Stop watching a wallet.\n" +
+			"/watched_wallets - List watched wallets.\n" +
+			"/gate_report - Token gate status of members."
 		c.SendAnswer(baseHelp + adminHelp)
 	} else {
 		c.SendAnswer(baseHelp)
@@ -0,0 +1,114 @@
+package commands
+
+import (
+	"consul-telegram-bot/internal/buybot"
+	"consul-telegram-bot/internal/metrics"
+	"consul-telegram-bot/internal/model"
+	"consul-telegram-bot/internal/router"
+	"consul-telegram-bot/internal/utils"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	holdersTimeout  = 2 * time.Minute
+	holdersCacheTTL = 5 * time.Minute
+)
+
+var holderStats = buybot.NewHolderStatsCache(holdersCacheTTL)
+
+var holderGrowthPeriods = []struct {
+	Name string
+	Days int
+}{
+	{Name: "24h", Days: 1},
+	{Name: "7d", Days: 7},
+	{Name: "30d", Days: 30},
+}
+
+func Holders(c *router.Context) {
+	tokenAddress := chatTokenAddress(c)
+	if tokenAddress == "" {
+		metrics.TelegramCommandsProcessed.WithLabelValues("holders", "error").Inc()
+		c.SendAnswer("🚧 Token address is not configured. Use /setup or set TOKEN_ADDRESS env.")
+		return
+	}
+
+	if c.Helius == nil {
+		metrics.TelegramCommandsProcessed.WithLabelValues("holders", "error").Inc()
+		c.SendAnswer("🚧 Holder stats are not available: HELIUS_RPC_URL is not configured.")
+		return
+	}
+
+	stats, err := holderStats.Get(tokenAddress, func() (*buybot.HolderStats, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), holdersTimeout)
+		defer cancel()
+
+		return buybot.LoadHolderStats(ctx, c.Helius, tokenAddress)
+	})
+	if err != nil {
+		c.Logger.Error("failed to load holders of %s: %s", tokenAddress, err)
+		metrics.TelegramCommandsProcessed.WithLabelValues("holders", "error").Inc()
+		metrics.ErrorsTotal.WithLabelValues("command", "holders_load_stats").Inc()
+		c.SendAnswer("🚧 Something went wrong. Please try again later.")
+		return
+	}
+
+	now := time.Now()
+	explorer := chatExplorer(c)
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("👥 <b>Holders</b> · <a href=\"%s\">%s</a>\n\n", explorer.TokenURL(tokenAddress), explorer.Name))
+	sb.WriteString(fmt.Sprintf("🪙 Supply: %s\n", utils.FormatTokenAmount(stats.Supply, stats.Decimals, "")))
+
+	if stats.HoldersKnown {
+		holders := utils.FormatCount(stats.Holders)
+		if !stats.HoldersComplete {
+			holders += "+"
+		}
+		sb.WriteString(fmt.Sprintf("👥 Holders: %s%s\n", holders, formatHolderGrowth(tokenAddress, stats.Holders, now)))
+	} else {
+		sb.WriteString("👥 Holders: unavailable\n")
+	}
+
+	sb.WriteString(fmt.Sprintf("🎯 Top 10: %s of supply <i>(excl. pools and burn)</i>\n", utils.FormatPercentage(stats.Top10Percent, 2)))
+
+	if len(stats.Largest) > 0 {
+		sb.WriteString("\n<b>Largest wallets:</b>\n")
+		for i, holder := range stats.Largest {
+			line := fmt.Sprintf("%d. <a href=\"%s\">%s</a> — %s", i+1, explorer.AccountURL(holder.Owner), utils.ShortenAddress(holder.Owner), utils.FormatPercentage(holder.Percent, 2))
+			if holder.Label != "" {
+				line += " · " + utils.EscapeHTML(holder.Label)
+			}
+			sb.WriteString(line + "\n")
+		}
+	}
+
+	metrics.TelegramCommandsProcessed.WithLabelValues("holders", "success").Inc()
+	c.SendAnswer(strings.TrimSuffix(sb.String(), "\n"))
+}
+
+func formatHolderGrowth(tokenAddress string, holders int, now time.Time) string {
+	var changes []string
+	for _, period := range holderGrowthPeriods {
+		snapshot, err := model.FindHolderSnapshot(tokenAddress, model.SnapshotDate(now.AddDate(0, 0, -period.Days)))
+		if err != nil {
+			continue
+		}
+
+		change := holders - snapshot.Holders
+		sign := "+"
+		if change < 0 {
+			sign = ""
+		}
+		changes = append(changes, fmt.Sprintf("%s%s %s", sign, utils.FormatCount(change), period.Name))
+	}
+
+	if len(changes) == 0 {
+		return ""
+	}
+
+	return " (" + strings.Join(changes, ", ") + ")"
+}
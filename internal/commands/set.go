@@ -5,6 +5,10 @@ import (
 	"consul-telegram-bot/internal/middlewares"
 	"consul-telegram-bot/internal/model"
 	"consul-telegram-bot/internal/router"
+	"consul-telegram-bot/internal/utils"
+	"fmt"
+	"net/url"
+	"strconv"
 	"strings"
 )
 
@@ -24,7 +28,17 @@ func setHandler(c *router.Context) {
 				"<code>website_url</code> — Website URL\n" +
 				"<code>token_address</code> — Token address\n" +
 				"<code>dex_url</code> — Dexscreener URL\n" +
-				"<code>axiom_url</code> — Axiom URL",
+				"<code>axiom_url</code> — Axiom URL\n" +
+				"<code>min_buy</code> — Minimum buy, e.g. <code>0.5</code> (SOL) or <code>$100</code>\n" +
+				"<code>tier</code> — Buy tier, e.g. <code>whale 10 🐳🐳🐳</code>, <code>whale off</code> to remove it or <code>reset</code> for the defaults\n" +
+				"<code>tier_gifs</code> — Tier GIF URLs, e.g. <code>whale https://...</code>, or <code>whale off</code>\n" +
+				"<code>min_sell</code> — Minimum sell in SOL, e.g. <code>5</code>\n" +
+				"<code>digest</code> — Buy digest window in minutes, or <code>off</code>\n" +
+				"<code>new_holders_only</code> — Announce only buys from new holders, <code>on</code> or <code>off</code>\n" +
+				"<code>venues</code> — Announce only buys via these venues, e.g. <code>raydium pumpswap</code>, or <code>all</code>\n" +
+				"<code>gate_min</code> — Minimum token balance to chat, or <code>off</code>\n" +
+				"<code>gate_action</code> — What to do with members below the minimum, <code>warn</code> or <code>remove</code>\n" +
+				"<code>explorer</code> — Block explorer for links, e.g. <code>solscan</code>",
 		)
 		return
 	}
@@ -41,6 +55,7 @@ func setHandler(c *router.Context) {
 	value := strings.Join(c.Args[1:], " ")
 
 	var fieldName string
+	result := "updated"
 	switch field {
 	case "name":
 		recipient.ProjectName = value
@@ -63,6 +78,136 @@ func setHandler(c *router.Context) {
 	case "axiom_url", "axiom":
 		recipient.AxiomURL = value
 		fieldName = "Axiom URL"
+	case "min_buy":
+		minSol, minUSD, err := parseMinBuy(value)
+		if err != nil {
+			metrics.TelegramCommandsProcessed.WithLabelValues("set", "error").Inc()
+			c.SendAnswer("🚧 Invalid minimum buy. Use <code>0.5</code> for SOL or <code>$100</code> for USD.")
+			return
+		}
+		recipient.MinBuySol = minSol
+		recipient.MinBuyUSD = minUSD
+		fieldName = "Minimum buy"
+	case "min_sell":
+		minSol, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(strings.ToLower(value), "sol")), 64)
+		if err != nil || minSol < 0 {
+			metrics.TelegramCommandsProcessed.WithLabelValues("set", "error").Inc()
+			c.SendAnswer("🚧 Invalid minimum sell. Use a SOL amount, e.g. <code>5</code>.")
+			return
+		}
+		recipient.MinSellSol = minSol
+		fieldName = "Minimum sell"
+	case "digest":
+		minutes := 0
+		if strings.ToLower(value) != "off" {
+			minutes, err = strconv.Atoi(strings.TrimSpace(strings.TrimSuffix(strings.ToLower(value), "m")))
+			if err != nil || minutes < 0 || minutes > 24*60 {
+				metrics.TelegramCommandsProcessed.WithLabelValues("set", "error").Inc()
+				c.SendAnswer("🚧 Invalid digest window. Use minutes, e.g. <code>5</code>, or <code>off</code>.")
+				return
+			}
+		}
+		recipient.BuyDigestMinutes = minutes
+		fieldName = "Buy digest"
+	case "new_holders_only":
+		switch strings.ToLower(value) {
+		case "on", "true", "yes":
+			recipient.NewHoldersOnly = true
+		case "off", "false", "no":
+			recipient.NewHoldersOnly = false
+		default:
+			metrics.TelegramCommandsProcessed.WithLabelValues("set", "error").Inc()
+			c.SendAnswer("🚧 Use <code>on</code> or <code>off</code>.")
+			return
+		}
+		fieldName = "New holders only"
+	case "venues":
+		venues, err := parseVenues(c.Args[1:])
+		if err != nil {
+			metrics.TelegramCommandsProcessed.WithLabelValues("set", "error").Inc()
+			c.SendAnswer("🚧 Invalid venues. Known venues: <code>" + strings.Join(model.Venues, "</code>, <code>") + "</code>, or <code>all</code>.")
+			return
+		}
+		recipient.Venues = venues
+		fieldName = "Venues"
+	case "gate_min":
+		minimum := 0.0
+		if strings.ToLower(value) != "off" {
+			minimum, err = strconv.ParseFloat(strings.ReplaceAll(value, ",", ""), 64)
+			if err != nil || minimum <= 0 {
+				metrics.TelegramCommandsProcessed.WithLabelValues("set", "error").Inc()
+				c.SendAnswer("🚧 Invalid minimum balance. Use a token amount, e.g. <code>100000</code>, or <code>off</code>.")
+				return
+			}
+		}
+		recipient.GateMinBalance = minimum
+		fieldName = "Gate minimum"
+	case "gate_action":
+		action := strings.ToLower(value)
+		if action != model.GateActionWarn && action != model.GateActionRemove {
+			metrics.TelegramCommandsProcessed.WithLabelValues("set", "error").Inc()
+			c.SendAnswer("🚧 Use <code>warn</code> or <code>remove</code>.")
+			return
+		}
+		recipient.GateAction = action
+		fieldName = "Gate action"
+	case "explorer":
+		explorer := model.FindExplorer(strings.ToLower(value))
+		if explorer == nil {
+			metrics.TelegramCommandsProcessed.WithLabelValues("set", "error").Inc()
+			c.SendAnswer("🚧 Unknown explorer. Known explorers: <code>" + strings.Join(model.ExplorerKeys(), "</code>, <code>") + "</code>.")
+			return
+		}
+		recipient.Explorer = explorer.Key
+		fieldName = "Explorer"
+	case "tier":
+		if len(c.Args) == 2 && strings.ToLower(c.Args[1]) == "reset" {
+			recipient.ResetBuyTiers()
+			fieldName = "Buy tiers"
+			result = "reset to the defaults"
+			break
+		}
+
+		if len(c.Args) == 3 && strings.ToLower(c.Args[2]) == "off" {
+			name := strings.ToLower(c.Args[1])
+			if !recipient.RemoveBuyTier(name) {
+				metrics.TelegramCommandsProcessed.WithLabelValues("set", "error").Inc()
+				c.SendAnswer("🚧 Unknown buy tier, or it is the last one: " + utils.EscapeHTML(name) + ". Use <code>/set tier reset</code> to restore the defaults.")
+				return
+			}
+			fieldName = "Buy tier " + utils.EscapeHTML(name)
+			result = "removed"
+			break
+		}
+
+		tier, err := parseBuyTier(c.Args[1:])
+		if err != nil {
+			metrics.TelegramCommandsProcessed.WithLabelValues("set", "error").Inc()
+			c.SendAnswer("🚧 Usage: /set tier <name> <min_sol> [emoji], /set tier <name> off or /set tier reset")
+			return
+		}
+		recipient.SetBuyTier(tier)
+		fieldName = "Buy tier " + utils.EscapeHTML(tier.Name)
+	case "tier_gifs":
+		gifs := c.Args[2:]
+		if len(gifs) == 1 && strings.ToLower(gifs[0]) == "off" {
+			gifs = nil
+		} else if len(gifs) == 0 || !allHTTPURLs(gifs) {
+			metrics.TelegramCommandsProcessed.WithLabelValues("set", "error").Inc()
+			c.SendAnswer("🚧 Usage: /set tier_gifs <name> <url> [url...] with http(s) links to GIFs, or /set tier_gifs <name> off")
+			return
+		}
+
+		name := strings.ToLower(c.Args[1])
+		if !recipient.SetBuyTierGifs(name, gifs) {
+			metrics.TelegramCommandsProcessed.WithLabelValues("set", "error").Inc()
+			c.SendAnswer("🚧 Unknown buy tier: " + utils.EscapeHTML(name))
+			return
+		}
+		fieldName = "GIFs for buy tier " + utils.EscapeHTML(name)
+		if gifs == nil {
+			result = "removed"
+		}
 	default:
 		metrics.TelegramCommandsProcessed.WithLabelValues("set", "error").Inc()
 		c.SendAnswer("🚧 Unknown field: " + field)
@@ -77,5 +222,77 @@ func setHandler(c *router.Context) {
 	}
 
 	metrics.TelegramCommandsProcessed.WithLabelValues("set", "success").Inc()
-	c.SendAnswer("✅ " + fieldName + " updated.")
+	c.SendAnswer("✅ " + fieldName + " " + result + ".")
+}
+
+func allHTTPURLs(values []string) bool {
+	for _, value := range values {
+		parsed, err := url.Parse(value)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return false
+		}
+	}
+
+	return true
+}
+
+func parseMinBuy(value string) (float64, float64, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+
+	isUSD := strings.HasPrefix(value, "$") || strings.HasSuffix(value, "usd")
+	value = strings.TrimPrefix(value, "$")
+	value = strings.TrimSpace(strings.TrimSuffix(strings.TrimSuffix(value, "usd"), "sol"))
+
+	amount, err := strconv.ParseFloat(value, 64)
+	if err != nil || amount < 0 {
+		return 0, 0, fmt.Errorf("invalid amount: %s", value)
+	}
+
+	if isUSD {
+		return 0, amount, nil
+	}
+
+	return amount, 0, nil
+}
+
+func parseBuyTier(args []string) (model.BuyTier, error) {
+	if len(args) < 2 {
+		return model.BuyTier{}, fmt.Errorf("not enough arguments")
+	}
+
+	minSol, err := strconv.ParseFloat(args[1], 64)
+	if err != nil || minSol < 0 {
+		return model.BuyTier{}, fmt.Errorf("invalid minimum: %s", args[1])
+	}
+
+	return model.BuyTier{
+		Name:   strings.ToLower(args[0]),
+		MinSol: minSol,
+		Emoji:  strings.Join(args[2:], " "),
+	}, nil
+}
+
+func parseVenues(args []string) ([]string, error) {
+	var venues []string
+
+	for _, arg := range args {
+		for _, venue := range strings.Split(strings.ToLower(arg), ",") {
+			venue = strings.TrimSpace(venue)
+			switch {
+			case venue == "":
+				continue
+			case venue == "all":
+				return nil, nil
+			case !model.IsKnownVenue(venue):
+				return nil, fmt.Errorf("unknown venue: %s", venue)
+			}
+			venues = append(venues, venue)
+		}
+	}
+
+	if len(venues) == 0 {
+		return nil, fmt.Errorf("no venues given")
+	}
+
+	return venues, nil
 }
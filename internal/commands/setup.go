@@ -5,7 +5,9 @@ import (
 	"consul-telegram-bot/internal/middlewares"
 	"consul-telegram-bot/internal/model"
 	"consul-telegram-bot/internal/router"
+	"consul-telegram-bot/internal/utils"
 	"fmt"
+	"strings"
 )
 
 func Setup(c *router.Context) {
@@ -55,6 +57,32 @@ func setupHandler(c *router.Context) {
 		status += fmt.Sprintf("%s <b>%s</b>%s\n", icon, f.desc, source)
 	}
 
+	minBuy := "none"
+	if recipient.MinBuySol > 0 {
+		minBuy = utils.FormatNumber(recipient.MinBuySol, "SOL")
+	} else if recipient.MinBuyUSD > 0 {
+		minBuy = utils.FormatUSD(recipient.MinBuyUSD)
+	}
+
+	buyAlerts := fmt.Sprintf("<b>Minimum buy:</b> %s\n", minBuy)
+	buyAlerts += fmt.Sprintf("<b>Explorer:</b> %s\n", recipient.GetExplorer().Name)
+	if recipient.NewHoldersOnly {
+		buyAlerts += "<b>New holders only:</b> on\n"
+	}
+	if len(recipient.Venues) > 0 {
+		buyAlerts += fmt.Sprintf("<b>Venues:</b> %s\n", strings.Join(recipient.Venues, ", "))
+	}
+	if recipient.GateMinBalance > 0 {
+		action := recipient.GateAction
+		if action == "" {
+			action = model.GateActionWarn
+		}
+		buyAlerts += fmt.Sprintf("<b>Gate:</b> %s tokens, %s\n", utils.FormatNumber(recipient.GateMinBalance, ""), action)
+	}
+	for _, tier := range recipient.GetBuyTiers() {
+		buyAlerts += fmt.Sprintf("<b>%s</b> from %s %s\n", utils.EscapeHTML(tier.Name), utils.FormatNumber(tier.MinSol, "SOL"), utils.EscapeHTML(tier.Emoji))
+	}
+
 	message := fmt.Sprintf(
 		"<b>🔧 Setup Wizard</b>\n\n"+
 			"<b>Progress:</b> %d/%d configured\n"+
@@ -62,6 +90,8 @@ func setupHandler(c *router.Context) {
 			"⚙️ = from env\n"+
 			"👻 = not configured\n\n"+
 			"%s\n"+
+			"<b>Buy alerts:</b>\n"+
+			"%s\n"+
 			"<b>Commands:</b>\n"+
 			"<code>/set name Aritect</code>\n"+
 			"<code>/set ticker TOKEN</code>\n"+
@@ -69,10 +99,13 @@ func setupHandler(c *router.Context) {
 			"<code>/set website_url https://example.com</code>\n"+
 			"<code>/set token_address ABC123...</code>\n"+
 			"<code>/set dex_url https://dexscreener.com/...</code>\n"+
-			"<code>/set axiom_url https://axiom.trade/...</code>",
+			"<code>/set axiom_url https://axiom.trade/...</code>\n"+
+			"<code>/set min_buy 0.5</code>\n"+
+			"<code>/set tier whale 10 🐳🐳🐳</code>",
 		configured,
 		total,
 		status,
+		buyAlerts,
 	)
 
 	c.SendAnswer(message)
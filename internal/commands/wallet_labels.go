@@ -0,0 +1,240 @@
+package commands
+
+import (
+	"consul-telegram-bot/internal/metrics"
+	"consul-telegram-bot/internal/middlewares"
+	"consul-telegram-bot/internal/model"
+	"consul-telegram-bot/internal/router"
+	"consul-telegram-bot/internal/utils"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const maxWalletLabelLength = 32
+
+type walletLabelEntry struct {
+	Line    int
+	Address string
+	Label   string
+}
+
+func LabelWallet(c *router.Context) {
+	middlewares.Manager(labelWalletHandler, c.Config.ManagerId)(c)
+}
+
+func IgnoreWallet(c *router.Context) {
+	middlewares.Manager(ignoreWalletHandler, c.Config.ManagerId)(c)
+}
+
+func UnlabelWallet(c *router.Context) {
+	middlewares.Manager(unlabelWalletHandler, c.Config.ManagerId)(c)
+}
+
+func WalletLabels(c *router.Context) {
+	middlewares.Manager(walletLabelsHandler, c.Config.ManagerId)(c)
+}
+
+func labelWalletHandler(c *router.Context) {
+	entries, skipped, ok := readWalletLabelEntries(c, "label_wallet")
+	if !ok {
+		return
+	}
+
+	if len(entries) == 0 && len(skipped) == 0 {
+		metrics.TelegramCommandsProcessed.WithLabelValues("label_wallet", "error").Inc()
+		c.SendAnswer("🚧 Usage: /label_wallet <address> <label>, or attach a .txt file with one <code>address label</code> per line.")
+		return
+	}
+
+	saved := 0
+	for _, entry := range entries {
+		if entry.Label == "" || len([]rune(entry.Label)) > maxWalletLabelLength {
+			skipped = append(skipped, entry.Line)
+			continue
+		}
+
+		label, err := model.FindWalletLabel(c.Message.Chat.ID, entry.Address)
+		if err != nil {
+			label = &model.WalletLabel{ChatID: c.Message.Chat.ID, Address: entry.Address}
+		}
+		label.Label = entry.Label
+
+		if err := label.Save(); err != nil {
+			metrics.TelegramCommandsProcessed.WithLabelValues("label_wallet", "error").Inc()
+			c.SendAnswer("🚧 Failed to save.")
+			return
+		}
+		saved++
+	}
+
+	if saved == 0 && c.Message.Document == nil {
+		metrics.TelegramCommandsProcessed.WithLabelValues("label_wallet", "error").Inc()
+		c.SendAnswer(fmt.Sprintf("🚧 Invalid address or label. Labels are up to %d characters.", maxWalletLabelLength))
+		return
+	}
+
+	metrics.TelegramCommandsProcessed.WithLabelValues("label_wallet", "success").Inc()
+	c.SendAnswer(fmt.Sprintf("✅ %d wallet(s) labeled.", saved) + formatSkippedLines(skipped))
+}
+
+func ignoreWalletHandler(c *router.Context) {
+	entries, skipped, ok := readWalletLabelEntries(c, "ignore_wallet")
+	if !ok {
+		return
+	}
+
+	if len(entries) == 0 && len(skipped) == 0 {
+		metrics.TelegramCommandsProcessed.WithLabelValues("ignore_wallet", "error").Inc()
+		c.SendAnswer("🚧 Usage: /ignore_wallet <address>, or attach a .txt file with one address per line.")
+		return
+	}
+
+	saved := 0
+	for _, entry := range entries {
+		label, err := model.FindWalletLabel(c.Message.Chat.ID, entry.Address)
+		if err != nil {
+			label = &model.WalletLabel{ChatID: c.Message.Chat.ID, Address: entry.Address}
+		}
+		label.Ignored = true
+		if label.Label == "" && entry.Label != "" && len([]rune(entry.Label)) <= maxWalletLabelLength {
+			label.Label = entry.Label
+		}
+
+		if err := label.Save(); err != nil {
+			metrics.TelegramCommandsProcessed.WithLabelValues("ignore_wallet", "error").Inc()
+			c.SendAnswer("🚧 Failed to save.")
+			return
+		}
+		saved++
+	}
+
+	if saved == 0 && c.Message.Document == nil {
+		metrics.TelegramCommandsProcessed.WithLabelValues("ignore_wallet", "error").Inc()
+		c.SendAnswer("🚧 Invalid address.")
+		return
+	}
+
+	metrics.TelegramCommandsProcessed.WithLabelValues("ignore_wallet", "success").Inc()
+	c.SendAnswer(fmt.Sprintf("✅ %d wallet(s) ignored, their buys and sells are no longer announced.", saved) + formatSkippedLines(skipped))
+}
+
+func unlabelWalletHandler(c *router.Context) {
+	if len(c.Args) == 0 {
+		metrics.TelegramCommandsProcessed.WithLabelValues("unlabel_wallet", "error").Inc()
+		c.SendAnswer("🚧 Usage: /unlabel_wallet <address>")
+		return
+	}
+
+	label, err := model.FindWalletLabel(c.Message.Chat.ID, c.Args[0])
+	if err != nil {
+		metrics.TelegramCommandsProcessed.WithLabelValues("unlabel_wallet", "error").Inc()
+		c.SendAnswer("🚧 Wallet not found. Use /wallet_labels to see the list.")
+		return
+	}
+
+	if err := label.Delete(); err != nil {
+		metrics.TelegramCommandsProcessed.WithLabelValues("unlabel_wallet", "error").Inc()
+		c.SendAnswer("🚧 Failed to save.")
+		return
+	}
+
+	metrics.TelegramCommandsProcessed.WithLabelValues("unlabel_wallet", "success").Inc()
+	c.SendAnswer(fmt.Sprintf("✅ Label and ignore status of <code>%s</code> removed.", label.Address))
+}
+
+func walletLabelsHandler(c *router.Context) {
+	labels := model.FindWalletLabels(c.Message.Chat.ID)
+
+	metrics.TelegramCommandsProcessed.WithLabelValues("wallet_labels", "success").Inc()
+
+	if len(labels) == 0 {
+		c.SendAnswer("🏷 No labeled or ignored wallets yet.\n\nUse /label_wallet &lt;address&gt; &lt;label&gt; or /ignore_wallet &lt;address&gt;.")
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("🏷 <b>Wallet Labels</b> (%d)\n\n", len(labels)))
+
+	for _, label := range labels {
+		line := fmt.Sprintf("<code>%s</code>", label.Address)
+		if label.Label != "" {
+			line += " — " + utils.EscapeHTML(label.Label)
+		}
+		if label.Ignored {
+			line += " 🚫"
+		}
+		sb.WriteString(line + "\n")
+	}
+
+	sb.WriteString("\n<i>🚫 = ignored. Use /unlabel_wallet &lt;address&gt; to remove an entry.</i>")
+
+	c.SendAnswer(sb.String())
+}
+
+func readWalletLabelEntries(c *router.Context, command string) ([]walletLabelEntry, []int, bool) {
+	var lines []string
+
+	if c.Message.Document != nil {
+		if !strings.HasSuffix(strings.ToLower(c.Message.Document.FileName), ".txt") {
+			metrics.TelegramCommandsProcessed.WithLabelValues(command, "error").Inc()
+			c.SendAnswer("🚧 Please provide a .txt file.")
+			return nil, nil, false
+		}
+
+		fileContent, err := downloadFile(c, c.Message.Document.FileID)
+		if err != nil {
+			c.Logger.Error("failed to download file: %s", err)
+			metrics.TelegramCommandsProcessed.WithLabelValues(command, "error").Inc()
+			c.SendAnswer("🚧 Failed to download file.")
+			return nil, nil, false
+		}
+		lines = strings.Split(fileContent, "\n")
+	} else if len(c.Args) > 0 {
+		lines = []string{c.GetArgString()}
+	}
+
+	var entries []walletLabelEntry
+	var skipped []int
+
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		address, label := splitWalletLabelLine(line)
+		if !utils.IsSolanaAddress(address) {
+			skipped = append(skipped, i+1)
+			continue
+		}
+
+		entries = append(entries, walletLabelEntry{Line: i + 1, Address: address, Label: label})
+	}
+
+	return entries, skipped, true
+}
+
+// splitWalletLabelLine splits an "address label" line on the first run of
+// spaces, tabs or commas, so CSV and tab-separated exports work as well.
+func splitWalletLabelLine(line string) (string, string) {
+	separator := strings.IndexAny(line, " \t,")
+	if separator < 0 {
+		return line, ""
+	}
+
+	return line[:separator], strings.TrimSpace(strings.TrimLeft(line[separator:], " \t,"))
+}
+
+func formatSkippedLines(skipped []int) string {
+	if len(skipped) == 0 {
+		return ""
+	}
+
+	numbers := make([]string, len(skipped))
+	for i, line := range skipped {
+		numbers[i] = strconv.Itoa(line)
+	}
+
+	return fmt.Sprintf("\n⚠️ Skipped %d invalid line(s): %s.", len(skipped), strings.Join(numbers, ", "))
+}
@@ -0,0 +1,32 @@
+package commands
+
+import "testing"
+
+func TestSplitWalletLabelLine(t *testing.T) {
+	const address = "3Kh6b2yAxT3NVvRwHLbGnXhZ1mLxq7pVtRn8cWfJe4Dk"
+
+	tests := []struct {
+		line      string
+		wantLabel string
+	}{
+		{line: address},
+		{line: address + " Team wallet", wantLabel: "Team wallet"},
+		{line: address + "\tTeam wallet", wantLabel: "Team wallet"},
+		{line: address + ",Team wallet", wantLabel: "Team wallet"},
+		{line: address + ", Team, multisig", wantLabel: "Team, multisig"},
+		{line: address + " \t Team wallet", wantLabel: "Team wallet"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			gotAddress, gotLabel := splitWalletLabelLine(tt.line)
+
+			if gotAddress != address {
+				t.Errorf("address = %q, want %q", gotAddress, address)
+			}
+			if gotLabel != tt.wantLabel {
+				t.Errorf("label = %q, want %q", gotLabel, tt.wantLabel)
+			}
+		})
+	}
+}
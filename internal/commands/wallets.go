@@ -0,0 +1,167 @@
+package commands
+
+import (
+	"consul-telegram-bot/internal/metrics"
+	"consul-telegram-bot/internal/model"
+	"consul-telegram-bot/internal/router"
+	"consul-telegram-bot/internal/utils"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	telebot "gopkg.in/telebot.v3"
+)
+
+func LinkWallet(c *router.Context) {
+	if c.Message.Chat.Type != telebot.ChatPrivate {
+		metrics.TelegramCommandsProcessed.WithLabelValues("link_wallet", "error").Inc()
+		c.SendAnswer("🔒 Please message me privately to link a wallet.")
+		return
+	}
+
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		metrics.TelegramCommandsProcessed.WithLabelValues("link_wallet", "error").Inc()
+		c.SendAnswer("🚧 Something went wrong. Please try again later.")
+		return
+	}
+
+	challenge := &model.WalletChallenge{
+		UserID:    c.Message.Sender.ID,
+		Nonce:     hex.EncodeToString(nonce),
+		ExpiresAt: time.Now().Add(model.WalletChallengeTTL).Unix(),
+	}
+
+	if err := challenge.Save(); err != nil {
+		metrics.TelegramCommandsProcessed.WithLabelValues("link_wallet", "error").Inc()
+		c.SendAnswer("🚧 Failed to save.")
+		return
+	}
+
+	metrics.TelegramCommandsProcessed.WithLabelValues("link_wallet", "success").Inc()
+	c.SendAnswer(fmt.Sprintf(
+		"🔗 <b>Link a wallet</b>\n\n"+
+			"Sign this exact message with your Solana wallet:\n\n"+
+			"<pre>%s</pre>\n\n"+
+			"Then send:\n<code>/verify_wallet &lt;address&gt; &lt;signature&gt;</code>\n\n"+
+			"<i>The message expires in %d minutes. Signing a message never moves funds.</i>",
+		utils.EscapeHTML(challenge.Message()),
+		int(model.WalletChallengeTTL.Minutes()),
+	))
+}
+
+func VerifyWallet(c *router.Context) {
+	if c.Message.Chat.Type != telebot.ChatPrivate {
+		metrics.TelegramCommandsProcessed.WithLabelValues("verify_wallet", "error").Inc()
+		c.SendAnswer("🔒 Please message me privately to link a wallet.")
+		return
+	}
+
+	if len(c.Args) < 2 {
+		metrics.TelegramCommandsProcessed.WithLabelValues("verify_wallet", "error").Inc()
+		c.SendAnswer("🚧 Usage: /verify_wallet <address> <signature>")
+		return
+	}
+
+	userID := c.Message.Sender.ID
+	address := c.Args[0]
+	signature := c.Args[1]
+
+	challenge, err := model.FindWalletChallenge(userID)
+	if err != nil || challenge.Expired() {
+		metrics.TelegramCommandsProcessed.WithLabelValues("verify_wallet", "error").Inc()
+		c.SendAnswer("🚧 No active verification. Run /link_wallet to get a new message to sign.")
+		return
+	}
+
+	if err := utils.VerifySolanaSignature(address, challenge.Message(), signature); err != nil {
+		metrics.TelegramCommandsProcessed.WithLabelValues("verify_wallet", "error").Inc()
+		c.SendAnswer("⚠️ Verification failed: " + utils.EscapeHTML(err.Error()) + ".")
+		return
+	}
+
+	if existing, err := model.FindUserWallet(address); err == nil && existing.UserID != userID {
+		metrics.TelegramCommandsProcessed.WithLabelValues("verify_wallet", "error").Inc()
+		c.SendAnswer("⚠️ This wallet is already linked to another Telegram account. It has to be unlinked there with /unlink_wallet first.")
+		return
+	}
+
+	wallet := &model.UserWallet{
+		Address:    address,
+		UserID:     userID,
+		Username:   c.Message.Sender.Username,
+		VerifiedAt: time.Now().Unix(),
+	}
+
+	if err := wallet.Save(); err != nil {
+		metrics.TelegramCommandsProcessed.WithLabelValues("verify_wallet", "error").Inc()
+		c.SendAnswer("🚧 Failed to save.")
+		return
+	}
+
+	challenge.Delete()
+
+	metrics.TelegramCommandsProcessed.WithLabelValues("verify_wallet", "success").Inc()
+	c.SendAnswer(fmt.Sprintf("✅ Wallet <code>%s</code> linked.", address))
+}
+
+func Wallets(c *router.Context) {
+	if c.Message.Chat.Type != telebot.ChatPrivate {
+		metrics.TelegramCommandsProcessed.WithLabelValues("wallets", "error").Inc()
+		c.SendAnswer("🔒 Please message me privately to see your linked wallets.")
+		return
+	}
+
+	wallets := model.FindWalletsByUser(c.Message.Sender.ID)
+
+	metrics.TelegramCommandsProcessed.WithLabelValues("wallets", "success").Inc()
+
+	if len(wallets) == 0 {
+		c.SendAnswer("👛 No linked wallets yet. Message me privately with /link_wallet to link one.")
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString("👛 <b>Linked Wallets</b>\n\n")
+
+	explorer := chatExplorer(c)
+	for i, wallet := range wallets {
+		sb.WriteString(fmt.Sprintf("%d. <code>%s</code> · <a href=\"%s\">%s</a>\n", i+1, wallet.Address, explorer.AccountURL(wallet.Address), explorer.Name))
+	}
+
+	sb.WriteString("\n<i>Use /unlink_wallet &lt;address&gt; to remove one.</i>")
+
+	c.SendAnswer(sb.String())
+}
+
+func UnlinkWallet(c *router.Context) {
+	if c.Message.Chat.Type != telebot.ChatPrivate {
+		metrics.TelegramCommandsProcessed.WithLabelValues("unlink_wallet", "error").Inc()
+		c.SendAnswer("🔒 Please message me privately to unlink a wallet.")
+		return
+	}
+
+	if len(c.Args) == 0 {
+		metrics.TelegramCommandsProcessed.WithLabelValues("unlink_wallet", "error").Inc()
+		c.SendAnswer("🚧 Usage: /unlink_wallet <address>")
+		return
+	}
+
+	wallet, err := model.FindUserWallet(c.Args[0])
+	if err != nil || wallet.UserID != c.Message.Sender.ID {
+		metrics.TelegramCommandsProcessed.WithLabelValues("unlink_wallet", "error").Inc()
+		c.SendAnswer("🚧 Wallet not found. Use /wallets to see your linked wallets.")
+		return
+	}
+
+	if err := wallet.Delete(); err != nil {
+		metrics.TelegramCommandsProcessed.WithLabelValues("unlink_wallet", "error").Inc()
+		c.SendAnswer("🚧 Failed to save.")
+		return
+	}
+
+	metrics.TelegramCommandsProcessed.WithLabelValues("unlink_wallet", "success").Inc()
+	c.SendAnswer(fmt.Sprintf("✅ Wallet <code>%s</code> unlinked.", wallet.Address))
+}
@@ -0,0 +1,120 @@
+package commands
+
+import (
+	"consul-telegram-bot/internal/metrics"
+	"consul-telegram-bot/internal/middlewares"
+	"consul-telegram-bot/internal/model"
+	"consul-telegram-bot/internal/router"
+	"consul-telegram-bot/internal/utils"
+	"fmt"
+	"strings"
+)
+
+func WatchWallet(c *router.Context) {
+	middlewares.Manager(watchWalletHandler, c.Config.ManagerId)(c)
+}
+
+func UnwatchWallet(c *router.Context) {
+	middlewares.Manager(unwatchWalletHandler, c.Config.ManagerId)(c)
+}
+
+func WatchedWallets(c *router.Context) {
+	middlewares.Manager(watchedWalletsHandler, c.Config.ManagerId)(c)
+}
+
+func watchWalletHandler(c *router.Context) {
+	if len(c.Args) < 2 {
+		metrics.TelegramCommandsProcessed.WithLabelValues("watch_wallet", "error").Inc()
+		c.SendAnswer("🚧 Usage: /watch_wallet <address> <label>, e.g. /watch_wallet 7xKX...AsU Treasury")
+		return
+	}
+
+	address := c.Args[0]
+	if !utils.IsSolanaAddress(address) {
+		metrics.TelegramCommandsProcessed.WithLabelValues("watch_wallet", "error").Inc()
+		c.SendAnswer("🚧 Invalid wallet address.")
+		return
+	}
+
+	label := strings.TrimSpace(strings.Join(c.Args[1:], " "))
+	if len([]rune(label)) > maxWalletLabelLength {
+		metrics.TelegramCommandsProcessed.WithLabelValues("watch_wallet", "error").Inc()
+		c.SendAnswer(fmt.Sprintf("🚧 Labels are up to %d characters.", maxWalletLabelLength))
+		return
+	}
+
+	wallet, err := model.FindWatchedWallet(c.Message.Chat.ID, address)
+	if err != nil {
+		if len(model.FindWatchedWallets(c.Message.Chat.ID)) >= model.MaxWatchedWallets {
+			metrics.TelegramCommandsProcessed.WithLabelValues("watch_wallet", "error").Inc()
+			c.SendAnswer(fmt.Sprintf("🚧 A chat can watch up to %d wallets. Remove one with /unwatch_wallet first.", model.MaxWatchedWallets))
+			return
+		}
+		wallet = &model.WatchedWallet{ChatID: c.Message.Chat.ID, Address: address}
+	}
+	wallet.Label = label
+
+	if err := wallet.Save(); err != nil {
+		metrics.TelegramCommandsProcessed.WithLabelValues("watch_wallet", "error").Inc()
+		c.SendAnswer("🚧 Failed to save.")
+		return
+	}
+
+	answer := fmt.Sprintf("✅ Watching <code>%s</code> as %s.", address, utils.EscapeHTML(label))
+
+	recipient, err := model.FindRecipient(c.Message.Chat.ID)
+	if err != nil || recipient.GetThreadIdForSignalType(model.SignalTypeWallets) == 0 {
+		answer += "\n⚠️ Movements are posted to the wallets thread, define it with /define_thread_id wallets."
+	}
+
+	metrics.TelegramCommandsProcessed.WithLabelValues("watch_wallet", "success").Inc()
+	c.SendAnswer(answer)
+}
+
+func unwatchWalletHandler(c *router.Context) {
+	if len(c.Args) == 0 {
+		metrics.TelegramCommandsProcessed.WithLabelValues("unwatch_wallet", "error").Inc()
+		c.SendAnswer("🚧 Usage: /unwatch_wallet <address>")
+		return
+	}
+
+	wallet, err := model.FindWatchedWallet(c.Message.Chat.ID, c.Args[0])
+	if err != nil {
+		metrics.TelegramCommandsProcessed.WithLabelValues("unwatch_wallet", "error").Inc()
+		c.SendAnswer("🚧 Wallet not found. Use /watched_wallets to see the list.")
+		return
+	}
+
+	if err := wallet.Delete(); err != nil {
+		metrics.TelegramCommandsProcessed.WithLabelValues("unwatch_wallet", "error").Inc()
+		c.SendAnswer("🚧 Failed to save.")
+		return
+	}
+
+	metrics.TelegramCommandsProcessed.WithLabelValues("unwatch_wallet", "success").Inc()
+	c.SendAnswer(fmt.Sprintf("✅ Stopped watching %s.", utils.EscapeHTML(wallet.Label)))
+}
+
+func watchedWalletsHandler(c *router.Context) {
+	wallets := model.FindWatchedWallets(c.Message.Chat.ID)
+
+	metrics.TelegramCommandsProcessed.WithLabelValues("watched_wallets", "success").Inc()
+
+	if len(wallets) == 0 {
+		c.SendAnswer("👀 No watched wallets yet.\n\nUse /watch_wallet &lt;address&gt; &lt;label&gt;.")
+		return
+	}
+
+	explorer := chatExplorer(c)
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("👀 <b>Watched Wallets</b> (%d/%d)\n\n", len(wallets), model.MaxWatchedWallets))
+
+	for _, wallet := range wallets {
+		sb.WriteString(fmt.Sprintf("<a href=\"%s\">%s</a> — <code>%s</code>\n", explorer.AccountURL(wallet.Address), utils.EscapeHTML(wallet.Label), wallet.Address))
+	}
+
+	sb.WriteString("\n<i>Use /unwatch_wallet &lt;address&gt; to stop watching a wallet.</i>")
+
+	c.SendAnswer(sb.String())
+}
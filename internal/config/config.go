@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
 )
 
 type Config struct {
@@ -10,6 +11,22 @@ type Config struct {
 	TelegramBotToken string
 	StorePath        string
 	HeliusRpcURL     string
+	HeliusWsURL      string
+	RpcFallbackURLs  []string
+
+	BuyBotMode              string
+	BuyBotBackfillLimit     int
+	BuyHistoryRetentionDays int
+	HeliusWebhookSecret     string
+	DexscreenerAPIURL       string
+
+	GateCheckIntervalMinutes int
+
+	SniperWindowSeconds      int
+	SniperMinSlotBuys        int
+	SniperMinSharedFunder    int
+	SniperFreshWalletMinutes int
+	SniperSupplyPercent      float64
 
 	ProjectName  string
 	TokenTicker  string
@@ -30,11 +47,62 @@ func New() *Config {
 		storePath = "./data/store"
 	}
 
+	buyBotMode := getEnvString("BUYBOT_MODE")
+	if buyBotMode == "" {
+		buyBotMode = "polling"
+	}
+
+	backfillLimit := int(getEnvInt64("BUYBOT_BACKFILL_LIMIT"))
+	if backfillLimit <= 0 {
+		backfillLimit = 1000
+	}
+
+	historyRetentionDays := int(getEnvInt64("BUY_HISTORY_RETENTION_DAYS"))
+	if historyRetentionDays <= 0 {
+		historyRetentionDays = 30
+	}
+
+	gateCheckInterval := int(getEnvInt64("GATE_CHECK_INTERVAL_MINUTES"))
+	if gateCheckInterval <= 0 {
+		gateCheckInterval = 60
+	}
+
+	sniperWindow := int(getEnvInt64("SNIPER_WINDOW_SECONDS"))
+	if sniperWindow <= 0 {
+		sniperWindow = 60
+	}
+
+	sniperFreshWallet := int(getEnvInt64("SNIPER_FRESH_WALLET_MINUTES"))
+	if sniperFreshWallet <= 0 {
+		sniperFreshWallet = 60
+	}
+
+	sniperSupplyPercent := getEnvFloat64("SNIPER_SUPPLY_PERCENT")
+	if sniperSupplyPercent <= 0 {
+		sniperSupplyPercent = 5
+	}
+
 	return &Config{
 		TelegramBotToken: getEnvString("TELEGRAM_BOT_TOKEN"),
 		ManagerId:        getEnvInt64("MANAGER_ID"),
 		StorePath:        storePath,
 		HeliusRpcURL:     getEnvString("HELIUS_RPC_URL"),
+		HeliusWsURL:      getEnvString("HELIUS_WS_URL"),
+		RpcFallbackURLs:  getEnvList("RPC_FALLBACK_URLS"),
+
+		BuyBotMode:              buyBotMode,
+		BuyBotBackfillLimit:     backfillLimit,
+		BuyHistoryRetentionDays: historyRetentionDays,
+		HeliusWebhookSecret:     getEnvString("HELIUS_WEBHOOK_SECRET"),
+		DexscreenerAPIURL:       getEnvString("DEXSCREENER_API_URL"),
+
+		GateCheckIntervalMinutes: gateCheckInterval,
+
+		SniperWindowSeconds:      sniperWindow,
+		SniperMinSlotBuys:        int(getEnvInt64WithDefault("SNIPER_MIN_SLOT_BUYS", 3)),
+		SniperMinSharedFunder:    int(getEnvInt64WithDefault("SNIPER_MIN_SHARED_FUNDER", 3)),
+		SniperFreshWalletMinutes: sniperFreshWallet,
+		SniperSupplyPercent:      sniperSupplyPercent,
 
 		ProjectName:  getEnvString("PROJECT_NAME"),
 		TokenTicker:  getEnvString("TOKEN_TICKER"),
@@ -50,10 +118,27 @@ func New() *Config {
 	}
 }
 
+func (c *Config) RpcURLs() []string {
+	if c.HeliusRpcURL == "" {
+		return nil
+	}
+	return append([]string{c.HeliusRpcURL}, c.RpcFallbackURLs...)
+}
+
 func getEnvString(key string) string {
 	return os.Getenv(key)
 }
 
+func getEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
 func getEnvInt64(key string) int64 {
 	s := os.Getenv(key)
 	number, err := strconv.ParseInt(s, 10, 64)
@@ -63,3 +148,19 @@ func getEnvInt64(key string) int64 {
 
 	return int64(number)
 }
+
+func getEnvInt64WithDefault(key string, defaultValue int64) int64 {
+	if _, ok := os.LookupEnv(key); !ok {
+		return defaultValue
+	}
+	return getEnvInt64(key)
+}
+
+func getEnvFloat64(key string) float64 {
+	number, err := strconv.ParseFloat(os.Getenv(key), 64)
+	if err != nil {
+		return 0
+	}
+
+	return number
+}
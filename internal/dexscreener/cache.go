@@ -0,0 +1,49 @@
+package dexscreener
+
+import (
+	"sync"
+	"time"
+)
+
+type cachedPair struct {
+	pair      Pair
+	expiresAt time.Time
+}
+
+type PairCache struct {
+	ttl     time.Duration
+	entries map[string]cachedPair
+	mu      sync.Mutex
+}
+
+func NewPairCache(ttl time.Duration) *PairCache {
+	return &PairCache{
+		ttl:     ttl,
+		entries: make(map[string]cachedPair),
+	}
+}
+
+func (c *PairCache) Get(key string, fetch func() (*Pair, error)) (*Pair, error) {
+	c.mu.Lock()
+	cached, ok := c.entries[key]
+	c.mu.Unlock()
+
+	if ok && time.Now().Before(cached.expiresAt) {
+		pair := cached.pair
+		return &pair, nil
+	}
+
+	pair, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.entries[key] = cachedPair{
+		pair:      *pair,
+		expiresAt: time.Now().Add(c.ttl),
+	}
+	c.mu.Unlock()
+
+	return pair, nil
+}
@@ -0,0 +1,159 @@
+package dexscreener
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const DefaultBaseURL = "https://api.dexscreener.com"
+
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+type Token struct {
+	Address string `json:"address"`
+	Name    string `json:"name"`
+	Symbol  string `json:"symbol"`
+}
+
+type TxnCount struct {
+	Buys  int `json:"buys"`
+	Sells int `json:"sells"`
+}
+
+type Liquidity struct {
+	Usd   float64 `json:"usd"`
+	Base  float64 `json:"base"`
+	Quote float64 `json:"quote"`
+}
+
+type Pair struct {
+	ChainID       string              `json:"chainId"`
+	DexID         string              `json:"dexId"`
+	URL           string              `json:"url"`
+	PairAddress   string              `json:"pairAddress"`
+	BaseToken     Token               `json:"baseToken"`
+	QuoteToken    Token               `json:"quoteToken"`
+	PriceNative   string              `json:"priceNative"`
+	PriceUsd      string              `json:"priceUsd"`
+	Txns          map[string]TxnCount `json:"txns"`
+	Volume        map[string]float64  `json:"volume"`
+	PriceChange   map[string]float64  `json:"priceChange"`
+	Liquidity     *Liquidity          `json:"liquidity"`
+	Fdv           float64             `json:"fdv"`
+	MarketCap     float64             `json:"marketCap"`
+	PairCreatedAt int64               `json:"pairCreatedAt"`
+}
+
+func NewClient(baseURL string) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+
+	return &Client{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{
+			Timeout: 15 * time.Second,
+		},
+	}
+}
+
+func (c *Client) GetTokenPairs(chainID, tokenAddress string) ([]Pair, error) {
+	var pairs []Pair
+	if err := c.get(fmt.Sprintf("/token-pairs/v1/%s/%s", chainID, tokenAddress), &pairs); err != nil {
+		return nil, err
+	}
+
+	return pairs, nil
+}
+
+func (c *Client) GetPair(chainID, pairAddress string) (*Pair, error) {
+	var response struct {
+		Pairs []Pair `json:"pairs"`
+	}
+	if err := c.get(fmt.Sprintf("/latest/dex/pairs/%s/%s", chainID, pairAddress), &response); err != nil {
+		return nil, err
+	}
+
+	if len(response.Pairs) == 0 {
+		return nil, fmt.Errorf("pair %s not found", pairAddress)
+	}
+
+	return &response.Pairs[0], nil
+}
+
+func (c *Client) get(path string, out interface{}) error {
+	resp, err := c.httpClient.Get(c.baseURL + path)
+	if err != nil {
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return nil
+}
+
+func (p *Pair) PriceUSD() float64 {
+	price, err := strconv.ParseFloat(p.PriceUsd, 64)
+	if err != nil {
+		return 0
+	}
+	return price
+}
+
+func (p *Pair) LiquidityUSD() float64 {
+	if p.Liquidity == nil {
+		return 0
+	}
+	return p.Liquidity.Usd
+}
+
+func MostLiquidPair(pairs []Pair, baseTokenAddress string) *Pair {
+	var best *Pair
+	for i := range pairs {
+		pair := &pairs[i]
+		if pair.BaseToken.Address != baseTokenAddress || pair.PriceUSD() == 0 {
+			continue
+		}
+
+		if best == nil || pair.LiquidityUSD() > best.LiquidityUSD() {
+			best = pair
+		}
+	}
+
+	return best
+}
+
+func ParsePairURL(pairURL string) (chainID string, pairAddress string, ok bool) {
+	parsed, err := url.Parse(pairURL)
+	if err != nil || !strings.HasSuffix(parsed.Host, "dexscreener.com") {
+		return "", "", false
+	}
+
+	parts := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+
+	return parts[0], parts[1], true
+}
@@ -0,0 +1,139 @@
+package dexscreener
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newFakeServer(t *testing.T, routes map[string]string) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := routes[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, body)
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestGetPair(t *testing.T) {
+	server := newFakeServer(t, map[string]string{
+		"/latest/dex/pairs/solana/Pair1": `{"pairs":[{"chainId":"solana","dexId":"raydium","pairAddress":"Pair1","baseToken":{"address":"Mint","symbol":"TOKEN"},"quoteToken":{"symbol":"SOL"},"priceUsd":"0.0123","priceChange":{"h1":-2.5,"h24":12.25},"liquidity":{"usd":45000}}]}`,
+		"/latest/dex/pairs/solana/Empty": `{"pairs":null}`,
+	})
+	client := NewClient(server.URL + "/")
+
+	pair, err := client.GetPair("solana", "Pair1")
+	if err != nil {
+		t.Fatalf("GetPair: %s", err)
+	}
+	if pair.DexID != "raydium" || pair.BaseToken.Symbol != "TOKEN" || pair.QuoteToken.Symbol != "SOL" {
+		t.Errorf("pair = %+v", pair)
+	}
+	if pair.PriceUSD() != 0.0123 {
+		t.Errorf("PriceUSD() = %v, want 0.0123", pair.PriceUSD())
+	}
+	if pair.LiquidityUSD() != 45000 {
+		t.Errorf("LiquidityUSD() = %v, want 45000", pair.LiquidityUSD())
+	}
+	if pair.PriceChange["h1"] != -2.5 || pair.PriceChange["h24"] != 12.25 {
+		t.Errorf("PriceChange = %v", pair.PriceChange)
+	}
+
+	if _, err := client.GetPair("solana", "Empty"); err == nil {
+		t.Error("GetPair with no pairs returned no error")
+	}
+	if _, err := client.GetPair("solana", "Missing"); err == nil {
+		t.Error("GetPair on a 404 returned no error")
+	}
+}
+
+func TestGetTokenPairsMostLiquid(t *testing.T) {
+	server := newFakeServer(t, map[string]string{
+		"/token-pairs/v1/solana/Mint": `[
+			{"pairAddress":"Small","baseToken":{"address":"Mint"},"priceUsd":"0.01","liquidity":{"usd":1000}},
+			{"pairAddress":"Unpriced","baseToken":{"address":"Mint"},"priceUsd":"","liquidity":{"usd":900000}},
+			{"pairAddress":"Quote","baseToken":{"address":"Other"},"priceUsd":"1","liquidity":{"usd":500000}},
+			{"pairAddress":"Large","baseToken":{"address":"Mint"},"priceUsd":"0.01","liquidity":{"usd":80000}}
+		]`,
+	})
+
+	pairs, err := NewClient(server.URL).GetTokenPairs("solana", "Mint")
+	if err != nil {
+		t.Fatalf("GetTokenPairs: %s", err)
+	}
+	if len(pairs) != 4 {
+		t.Fatalf("got %d pairs, want 4", len(pairs))
+	}
+
+	if best := MostLiquidPair(pairs, "Mint"); best == nil || best.PairAddress != "Large" {
+		t.Errorf("MostLiquidPair = %+v, want Large", best)
+	}
+	if best := MostLiquidPair(pairs, "Unknown"); best != nil {
+		t.Errorf("MostLiquidPair for an unknown token = %+v, want nil", best)
+	}
+}
+
+func TestPairCache(t *testing.T) {
+	cache := NewPairCache(time.Minute)
+
+	calls := 0
+	fetch := func() (*Pair, error) {
+		calls++
+		return &Pair{PairAddress: "Pair1"}, nil
+	}
+
+	for i := 0; i < 3; i++ {
+		pair, err := cache.Get("solana/Pair1", fetch)
+		if err != nil || pair.PairAddress != "Pair1" {
+			t.Fatalf("Get = %+v, %v", pair, err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("fetched %d times, want 1", calls)
+	}
+
+	if _, err := cache.Get("solana/Pair2", func() (*Pair, error) { return nil, errors.New("boom") }); err == nil {
+		t.Error("Get did not return the fetch error")
+	}
+	if _, err := cache.Get("solana/Pair2", fetch); err != nil || calls != 2 {
+		t.Errorf("failed fetch was cached: err %v, calls %d", err, calls)
+	}
+
+	expired := NewPairCache(0)
+	expired.Get("solana/Pair1", fetch)
+	expired.Get("solana/Pair1", fetch)
+	if calls != 4 {
+		t.Errorf("expired entry was served from the cache, calls %d, want 4", calls)
+	}
+}
+
+func TestParsePairURL(t *testing.T) {
+	tests := []struct {
+		url       string
+		wantChain string
+		wantPair  string
+		wantOK    bool
+	}{
+		{url: "https://dexscreener.com/solana/Pair1", wantChain: "solana", wantPair: "Pair1", wantOK: true},
+		{url: "https://www.dexscreener.com/solana/Pair1/", wantChain: "solana", wantPair: "Pair1", wantOK: true},
+		{url: "https://dexscreener.com/solana"},
+		{url: "https://birdeye.so/solana/Pair1"},
+	}
+
+	for _, tt := range tests {
+		chain, pair, ok := ParsePairURL(tt.url)
+		if chain != tt.wantChain || pair != tt.wantPair || ok != tt.wantOK {
+			t.Errorf("ParsePairURL(%q) = %q, %q, %v", tt.url, chain, pair, ok)
+		}
+	}
+}
@@ -0,0 +1,313 @@
+package gating
+
+import (
+	"consul-telegram-bot/internal/bot"
+	"consul-telegram-bot/internal/buybot"
+	"consul-telegram-bot/internal/config"
+	"consul-telegram-bot/internal/logger"
+	"consul-telegram-bot/internal/model"
+	"consul-telegram-bot/internal/utils"
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	telebot "gopkg.in/telebot.v3"
+)
+
+const balanceTimeout = 30 * time.Second
+
+type Gatekeeper struct {
+	bot    *bot.Bot
+	client *buybot.HeliusClient
+	logger *logger.Logger
+	config *config.Config
+}
+
+func New(botInstance *bot.Bot, client *buybot.HeliusClient, logger *logger.Logger, cfg *config.Config) *Gatekeeper {
+	return &Gatekeeper{
+		bot:    botInstance,
+		client: client,
+		logger: logger,
+		config: cfg,
+	}
+}
+
+func (g *Gatekeeper) HandleUserJoined(m *telebot.Message) {
+	recipient, err := model.FindRecipient(m.Chat.ID)
+	if err != nil || recipient.GateMinBalance <= 0 {
+		return
+	}
+
+	users := m.UsersJoined
+	if len(users) == 0 && m.UserJoined != nil {
+		users = []telebot.User{*m.UserJoined}
+	}
+
+	for i := range users {
+		user := &users[i]
+		if user.IsBot {
+			continue
+		}
+
+		member := g.trackMember(recipient.Id, user)
+		passing, err := g.checkMember(recipient, member)
+		if err != nil {
+			g.logger.Error("failed to check holdings of user %d, leaving them unrestricted: %s", member.UserID, err)
+			continue
+		}
+		if passing || g.isExempt(recipient.Id, member.UserID) {
+			continue
+		}
+
+		if err := g.restrict(recipient.Id, member.UserID); err != nil {
+			g.logger.Error("failed to restrict user %d in chat %d: %s", member.UserID, recipient.Id, err)
+			continue
+		}
+
+		member.Restricted = true
+		member.Save()
+
+		g.send(recipient.Id, fmt.Sprintf(
+			"👋 %s, this chat is for holders of at least %s. "+
+				"Message me privately with /link_wallet to verify a wallet and you will be let in automatically.",
+			utils.EscapeHTML(member.DisplayName()),
+			g.formatMinimum(recipient),
+		))
+	}
+}
+
+func (g *Gatekeeper) TrackMessage(m *telebot.Message) {
+	if m.Sender == nil || m.Sender.IsBot || (m.Chat.Type != telebot.ChatGroup && m.Chat.Type != telebot.ChatSuperGroup) {
+		return
+	}
+
+	recipient, err := model.FindRecipient(m.Chat.ID)
+	if err != nil || recipient.GateMinBalance <= 0 {
+		return
+	}
+
+	if _, err := model.FindGateMember(recipient.Id, m.Sender.ID); err == nil {
+		return
+	}
+
+	g.trackMember(recipient.Id, m.Sender)
+}
+
+func (g *Gatekeeper) HandleWalletsChanged(userID int64) {
+	for _, recipient := range gatedRecipients() {
+		member, err := model.FindGateMember(recipient.Id, userID)
+		if err != nil {
+			continue
+		}
+		g.enforce(recipient, member)
+	}
+}
+
+func (g *Gatekeeper) Run() {
+	interval := time.Duration(g.config.GateCheckIntervalMinutes) * time.Minute
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		for _, recipient := range gatedRecipients() {
+			members := model.FindGateMembers(recipient.Id)
+			g.logger.Info("re-checking %d members of gated chat %d", len(members), recipient.Id)
+
+			for _, member := range members {
+				g.enforce(recipient, member)
+			}
+		}
+	}
+}
+
+func (g *Gatekeeper) enforce(recipient *model.Recipient, member *model.GateMember) {
+	passing, err := g.checkMember(recipient, member)
+	if err != nil {
+		g.logger.Error("failed to check holdings of user %d, skipping: %s", member.UserID, err)
+		return
+	}
+
+	if passing {
+		if member.Restricted {
+			if err := g.unrestrict(recipient.Id, member.UserID); err != nil {
+				g.logger.Error("failed to lift restriction of user %d in chat %d: %s", member.UserID, recipient.Id, err)
+				return
+			}
+			member.Restricted = false
+			g.send(recipient.Id, fmt.Sprintf("✅ %s is verified as a holder, welcome!", utils.EscapeHTML(member.DisplayName())))
+		}
+		member.Warned = false
+		member.Save()
+		return
+	}
+
+	if member.Restricted || g.isExempt(recipient.Id, member.UserID) {
+		return
+	}
+
+	if recipient.GateAction == model.GateActionRemove {
+		if err := g.remove(recipient.Id, member.UserID); err != nil {
+			g.logger.Error("failed to remove user %d from chat %d: %s", member.UserID, recipient.Id, err)
+			return
+		}
+
+		member.Delete()
+		g.send(recipient.Id, fmt.Sprintf("🚪 %s was removed for holding less than %s.", utils.EscapeHTML(member.DisplayName()), g.formatMinimum(recipient)))
+		return
+	}
+
+	if member.Warned {
+		return
+	}
+
+	member.Warned = true
+	member.Save()
+
+	g.send(recipient.Id, fmt.Sprintf(
+		"⚠️ %s, your verified wallets hold less than %s, the minimum for this chat. "+
+			"Link a wallet with /link_wallet in a private chat with me.",
+		utils.EscapeHTML(member.DisplayName()),
+		g.formatMinimum(recipient),
+	))
+}
+
+// checkMember reports whether the member holds enough tokens. An error means
+// the balance is unknown and no action should be taken.
+func (g *Gatekeeper) checkMember(recipient *model.Recipient, member *model.GateMember) (bool, error) {
+	tokenAddress := model.GetWithFallback(recipient.TokenAddress, g.config.TokenAddress)
+
+	balance, decimals, err := g.holdings(member.UserID, tokenAddress)
+	if err != nil {
+		return false, err
+	}
+
+	member.Passing = balance.Cmp(minimumRaw(recipient.GateMinBalance, decimals)) >= 0
+	member.Balance = balance.String()
+	member.Decimals = decimals
+	member.CheckedAt = time.Now().Unix()
+	member.Save()
+
+	return member.Passing, nil
+}
+
+// isExempt reports whether the user administers the chat. When the role
+// cannot be read, the user is treated as exempt so no action is taken.
+func (g *Gatekeeper) isExempt(chatID, userID int64) bool {
+	chatMember, err := g.bot.Bot.ChatMemberOf(&telebot.Chat{ID: chatID}, &telebot.User{ID: userID})
+	if err != nil {
+		g.logger.Error("failed to get role of user %d in chat %d, skipping: %s", userID, chatID, err)
+		return true
+	}
+
+	return chatMember.Role == telebot.Administrator || chatMember.Role == telebot.Creator
+}
+
+func (g *Gatekeeper) holdings(userID int64, tokenAddress string) (*big.Int, int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), balanceTimeout)
+	defer cancel()
+
+	total := new(big.Int)
+	var decimals int
+
+	for _, wallet := range model.FindWalletsByUser(userID) {
+		balance, walletDecimals, err := g.client.GetTokenBalance(ctx, wallet.Address, tokenAddress)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to get balance of %s: %w", wallet.Address, err)
+		}
+
+		total.Add(total, balance)
+		if walletDecimals > 0 {
+			decimals = walletDecimals
+		}
+	}
+
+	return total, decimals, nil
+}
+
+func (g *Gatekeeper) trackMember(chatID int64, user *telebot.User) *model.GateMember {
+	member, err := model.FindGateMember(chatID, user.ID)
+	if err != nil {
+		member = &model.GateMember{
+			ChatID: chatID,
+			UserID: user.ID,
+		}
+	}
+
+	member.Username = user.Username
+	member.Name = strings.TrimSpace(user.FirstName + " " + user.LastName)
+
+	if err := member.Save(); err != nil {
+		g.logger.Error("failed to save gate member %d in chat %d: %s", user.ID, chatID, err)
+	}
+
+	return member
+}
+
+func (g *Gatekeeper) restrict(chatID, userID int64) error {
+	return g.bot.Bot.Restrict(&telebot.Chat{ID: chatID}, &telebot.ChatMember{
+		User:            &telebot.User{ID: userID},
+		Rights:          telebot.NoRights(),
+		RestrictedUntil: telebot.Forever(),
+	})
+}
+
+func (g *Gatekeeper) unrestrict(chatID, userID int64) error {
+	chat, err := g.bot.Bot.ChatByID(chatID)
+	if err != nil {
+		return fmt.Errorf("failed to get chat permissions: %w", err)
+	}
+
+	if chat.Permissions == nil {
+		return fmt.Errorf("chat %d has no default permissions", chatID)
+	}
+
+	return g.bot.Bot.Restrict(chat, &telebot.ChatMember{
+		User:            &telebot.User{ID: userID},
+		Rights:          *chat.Permissions,
+		RestrictedUntil: telebot.Forever(),
+	})
+}
+
+func (g *Gatekeeper) remove(chatID, userID int64) error {
+	chat := &telebot.Chat{ID: chatID}
+	user := &telebot.User{ID: userID}
+
+	if err := g.bot.Bot.Ban(chat, &telebot.ChatMember{User: user}); err != nil {
+		return err
+	}
+
+	return g.bot.Bot.Unban(chat, user)
+}
+
+func (g *Gatekeeper) send(chatID int64, text string) {
+	if _, err := g.bot.Bot.Send(&telebot.Chat{ID: chatID}, text, &telebot.SendOptions{ParseMode: "HTML"}); err != nil {
+		g.logger.Error("failed to send gate message to chat %d: %s", chatID, err)
+	}
+}
+
+func (g *Gatekeeper) formatMinimum(recipient *model.Recipient) string {
+	ticker := model.GetWithFallback(recipient.TokenTicker, g.config.TokenTicker)
+	if ticker == "" {
+		ticker = "TOKEN"
+	}
+	return utils.FormatNumber(recipient.GateMinBalance, "$"+ticker)
+}
+
+func gatedRecipients() []*model.Recipient {
+	var recipients []*model.Recipient
+	for _, recipient := range model.FindAllRecipients() {
+		if recipient.GateMinBalance > 0 {
+			recipients = append(recipients, recipient)
+		}
+	}
+	return recipients
+}
+
+func minimumRaw(minimum float64, decimals int) *big.Int {
+	scale := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil))
+	raw, _ := new(big.Float).Mul(big.NewFloat(minimum), scale).Int(nil)
+	return raw
+}
@@ -86,6 +86,23 @@ var (
 		[]string{"component", "error_type"},
 	)
 
+	RPCRequestDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "consul_telegram_bot_rpc_request_duration_seconds",
+			Help:    "Solana RPC request latency in seconds",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"method", "endpoint"},
+	)
+
+	RPCErrorsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "consul_telegram_bot_rpc_errors_total",
+			Help: "Total number of failed Solana RPC requests",
+		},
+		[]string{"method", "endpoint", "error_type"},
+	)
+
 	UptimeSeconds = prometheus.NewGauge(
 		prometheus.GaugeOpts{
 			Name: "consul_telegram_bot_uptime_seconds",
@@ -104,10 +121,16 @@ func init() {
 		LevelDBSize,
 		ProcessingDuration,
 		ErrorsTotal,
+		RPCRequestDuration,
+		RPCErrorsTotal,
 		UptimeSeconds,
 	)
 }
 
+func RegisterHandler(pattern string, handler http.Handler) {
+	http.Handle(pattern, handler)
+}
+
 func StartMetricsServer(port string) {
 	http.Handle("/metrics", promhttp.Handler())
 
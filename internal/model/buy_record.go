@@ -0,0 +1,188 @@
+package model
+
+import (
+	"consul-telegram-bot/internal/store"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+type BuyRecord struct {
+	Mint      string  `msgpack:"mint"`
+	Signature string  `msgpack:"signature"`
+	Buyer     string  `msgpack:"buyer"`
+	Amount    string  `msgpack:"amount"`
+	Decimals  int     `msgpack:"decimals"`
+	SolAmount float64 `msgpack:"sol_amount"`
+	USDValue  float64 `msgpack:"usd_value"`
+	Timestamp int64   `msgpack:"timestamp"`
+}
+
+type BuyStats struct {
+	Count        int
+	SolVolume    float64
+	USDVolume    float64
+	UniqueBuyers int
+}
+
+type BuyerVolume struct {
+	Buyer     string
+	Count     int
+	SolAmount float64
+	USDValue  float64
+}
+
+func (r *BuyRecord) Save() error {
+	key := GetBuyRecordKey(r.Mint, r.Timestamp, r.Signature)
+	data, err := msgpack.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	storeInstance := store.GetInstance()
+	return storeInstance.Put(key, data)
+}
+
+func FindBuyRecords(mint string, since time.Time) ([]*BuyRecord, error) {
+	storeInstance := store.GetInstance()
+	iterator := storeInstance.PrefixIterator(getBuyRecordPrefix(mint), GetBuyRecordKey(mint, since.Unix(), ""))
+	defer iterator.Release()
+
+	records := make([]*BuyRecord, 0)
+
+	for iterator.Next() {
+		var record BuyRecord
+		if err := msgpack.Unmarshal(iterator.Value(), &record); err != nil {
+			continue
+		}
+
+		records = append(records, &record)
+	}
+
+	return records, iterator.Error()
+}
+
+// GetBuyStatsSince returns the buy stats for each of the given start times,
+// reading the records of the longest window once.
+func GetBuyStatsSince(mint string, since []time.Time) ([]*BuyStats, error) {
+	if len(since) == 0 {
+		return nil, nil
+	}
+
+	oldest := since[0]
+	for _, start := range since[1:] {
+		if start.Before(oldest) {
+			oldest = start
+		}
+	}
+
+	records, err := FindBuyRecords(mint, oldest)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make([]*BuyStats, len(since))
+	buyers := make([]map[string]bool, len(since))
+	for i := range since {
+		stats[i] = &BuyStats{}
+		buyers[i] = make(map[string]bool)
+	}
+
+	for _, record := range records {
+		for i, start := range since {
+			if record.Timestamp < start.Unix() {
+				continue
+			}
+
+			stats[i].Count++
+			stats[i].SolVolume += record.SolAmount
+			stats[i].USDVolume += record.USDValue
+			buyers[i][record.Buyer] = true
+		}
+	}
+
+	for i := range stats {
+		stats[i].UniqueBuyers = len(buyers[i])
+	}
+
+	return stats, nil
+}
+
+func GetTopBuyers(mint string, since time.Time, limit int) ([]*BuyerVolume, error) {
+	records, err := FindBuyRecords(mint, since)
+	if err != nil {
+		return nil, err
+	}
+
+	byBuyer := make(map[string]*BuyerVolume)
+	for _, record := range records {
+		volume, ok := byBuyer[record.Buyer]
+		if !ok {
+			volume = &BuyerVolume{Buyer: record.Buyer}
+			byBuyer[record.Buyer] = volume
+		}
+
+		volume.Count++
+		volume.SolAmount += record.SolAmount
+		volume.USDValue += record.USDValue
+	}
+
+	buyers := make([]*BuyerVolume, 0, len(byBuyer))
+	for _, volume := range byBuyer {
+		buyers = append(buyers, volume)
+	}
+
+	sort.Slice(buyers, func(i, j int) bool {
+		return buyers[i].SolAmount > buyers[j].SolAmount
+	})
+
+	if len(buyers) > limit {
+		buyers = buyers[:limit]
+	}
+
+	return buyers, nil
+}
+
+func DeleteOldBuyRecords(maxAge time.Duration) (int, error) {
+	storeInstance := store.GetInstance()
+	iterator := storeInstance.PrefixIterator([]byte("buy:"), nil)
+	defer iterator.Release()
+
+	cutoff := time.Now().Add(-maxAge).Unix()
+	deleted := 0
+
+	keysToDelete := make([][]byte, 0)
+
+	for iterator.Next() {
+		key := iterator.Key()
+
+		var record BuyRecord
+		if err := msgpack.Unmarshal(iterator.Value(), &record); err != nil {
+			continue
+		}
+
+		if record.Timestamp < cutoff {
+			keyCopy := make([]byte, len(key))
+			copy(keyCopy, key)
+			keysToDelete = append(keysToDelete, keyCopy)
+		}
+	}
+
+	for _, key := range keysToDelete {
+		if err := storeInstance.Delete(key); err == nil {
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
+
+func GetBuyRecordKey(mint string, timestamp int64, signature string) []byte {
+	return []byte(fmt.Sprintf("buy:%s:%020d:%s", mint, timestamp, signature))
+}
+
+func getBuyRecordPrefix(mint string) []byte {
+	return []byte(fmt.Sprintf("buy:%s:", mint))
+}
@@ -0,0 +1,26 @@
+package model
+
+const MaxBuyButtons = 4
+
+type BuyButton struct {
+	Text string
+	URL  string
+}
+
+func (r *Recipient) AddBuyButton(button BuyButton) bool {
+	if len(r.BuyButtons) >= MaxBuyButtons {
+		return false
+	}
+
+	r.BuyButtons = append(r.BuyButtons, button)
+	return true
+}
+
+func (r *Recipient) RemoveBuyButton(index int) bool {
+	if index < 0 || index >= len(r.BuyButtons) {
+		return false
+	}
+
+	r.BuyButtons = append(r.BuyButtons[:index], r.BuyButtons[index+1:]...)
+	return true
+}
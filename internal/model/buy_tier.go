@@ -0,0 +1,93 @@
+package model
+
+import "sort"
+
+type BuyTier struct {
+	Name   string
+	MinSol float64
+	Emoji  string
+	Gifs   []string
+}
+
+var DefaultBuyTiers = []BuyTier{
+	{Name: "shrimp", MinSol: 0, Emoji: "🥬🥦🌿🌵🌳☘️"},
+	{Name: "dolphin", MinSol: 1, Emoji: "🐬🐬🐬🐬🐬🐬"},
+	{Name: "whale", MinSol: 10, Emoji: "🐳🐳🐳🐳🐳🐳"},
+}
+
+func (r *Recipient) GetBuyTiers() []BuyTier {
+	if len(r.BuyTiers) > 0 {
+		return r.BuyTiers
+	}
+	return DefaultBuyTiers
+}
+
+func (r *Recipient) SetBuyTier(tier BuyTier) {
+	tiers := make([]BuyTier, 0, len(r.GetBuyTiers())+1)
+	replaced := false
+
+	for _, t := range r.GetBuyTiers() {
+		if t.Name == tier.Name {
+			t.MinSol = tier.MinSol
+			if tier.Emoji != "" {
+				t.Emoji = tier.Emoji
+			}
+			replaced = true
+		}
+		tiers = append(tiers, t)
+	}
+
+	if !replaced {
+		tiers = append(tiers, tier)
+	}
+
+	sort.Slice(tiers, func(i, j int) bool {
+		return tiers[i].MinSol < tiers[j].MinSol
+	})
+
+	r.BuyTiers = tiers
+}
+
+func (r *Recipient) RemoveBuyTier(name string) bool {
+	tiers := make([]BuyTier, 0, len(r.GetBuyTiers()))
+	for _, t := range r.GetBuyTiers() {
+		if t.Name != name {
+			tiers = append(tiers, t)
+		}
+	}
+
+	if len(tiers) == len(r.GetBuyTiers()) || len(tiers) == 0 {
+		return false
+	}
+
+	r.BuyTiers = tiers
+	return true
+}
+
+func (r *Recipient) ResetBuyTiers() {
+	r.BuyTiers = nil
+}
+
+func (r *Recipient) SetBuyTierGifs(name string, gifs []string) bool {
+	tiers := append([]BuyTier(nil), r.GetBuyTiers()...)
+
+	for i := range tiers {
+		if tiers[i].Name == name {
+			tiers[i].Gifs = gifs
+			r.BuyTiers = tiers
+			return true
+		}
+	}
+
+	return false
+}
+
+func FindBuyTier(tiers []BuyTier, solAmount float64) *BuyTier {
+	var found *BuyTier
+	for i := range tiers {
+		if solAmount >= tiers[i].MinSol && (found == nil || tiers[i].MinSol >= found.MinSol) {
+			found = &tiers[i]
+		}
+	}
+	return found
+}
@@ -0,0 +1,85 @@
+package model
+
+import "fmt"
+
+const (
+	ExplorerSolscan        = "solscan"
+	ExplorerSolanaExplorer = "solana"
+	ExplorerSolanaFM       = "solanafm"
+	ExplorerOrb            = "orb"
+)
+
+type Explorer struct {
+	Key           string
+	Name          string
+	txFormat      string
+	accountFormat string
+	tokenFormat   string
+}
+
+var Explorers = []*Explorer{
+	{
+		Key:           ExplorerSolscan,
+		Name:          "Solscan",
+		txFormat:      "https://solscan.io/tx/%s",
+		accountFormat: "https://solscan.io/account/%s",
+		tokenFormat:   "https://solscan.io/token/%s",
+	},
+	{
+		Key:           ExplorerSolanaExplorer,
+		Name:          "Solana Explorer",
+		txFormat:      "https://explorer.solana.com/tx/%s",
+		accountFormat: "https://explorer.solana.com/address/%s",
+		tokenFormat:   "https://explorer.solana.com/address/%s",
+	},
+	{
+		Key:           ExplorerSolanaFM,
+		Name:          "SolanaFM",
+		txFormat:      "https://solana.fm/tx/%s",
+		accountFormat: "https://solana.fm/address/%s",
+		tokenFormat:   "https://solana.fm/address/%s",
+	},
+	{
+		Key:           ExplorerOrb,
+		Name:          "Orb",
+		txFormat:      "https://orbmarkets.io/tx/%s",
+		accountFormat: "https://orbmarkets.io/address/%s",
+		tokenFormat:   "https://orbmarkets.io/token/%s",
+	},
+}
+
+func FindExplorer(key string) *Explorer {
+	for _, explorer := range Explorers {
+		if explorer.Key == key {
+			return explorer
+		}
+	}
+	return nil
+}
+
+func ExplorerKeys() []string {
+	keys := make([]string, len(Explorers))
+	for i, explorer := range Explorers {
+		keys[i] = explorer.Key
+	}
+	return keys
+}
+
+func (e *Explorer) TxURL(signature string) string {
+	return fmt.Sprintf(e.txFormat, signature)
+}
+
+func (e *Explorer) AccountURL(address string) string {
+	return fmt.Sprintf(e.accountFormat, address)
+}
+
+func (e *Explorer) TokenURL(mint string) string {
+	return fmt.Sprintf(e.tokenFormat, mint)
+}
+
+func (r *Recipient) GetExplorer() *Explorer {
+	if explorer := FindExplorer(r.Explorer); explorer != nil {
+		return explorer
+	}
+	return Explorers[0]
+}
@@ -0,0 +1,101 @@
+package model
+
+import (
+	"bytes"
+	"consul-telegram-bot/internal/store"
+	"fmt"
+	"sort"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+const (
+	GateActionWarn   = "warn"
+	GateActionRemove = "remove"
+)
+
+type GateMember struct {
+	ChatID     int64  `msgpack:"chat_id"`
+	UserID     int64  `msgpack:"user_id"`
+	Username   string `msgpack:"username"`
+	Name       string `msgpack:"name"`
+	Passing    bool   `msgpack:"passing"`
+	Balance    string `msgpack:"balance"`
+	Decimals   int    `msgpack:"decimals"`
+	Restricted bool   `msgpack:"restricted"`
+	Warned     bool   `msgpack:"warned"`
+	CheckedAt  int64  `msgpack:"checked_at"`
+}
+
+func (m *GateMember) Save() error {
+	key := GetGateMemberKey(m.ChatID, m.UserID)
+	data, err := msgpack.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	storeInstance := store.GetInstance()
+	return storeInstance.Put(key, data)
+}
+
+func (m *GateMember) Delete() error {
+	storeInstance := store.GetInstance()
+	return storeInstance.Delete(GetGateMemberKey(m.ChatID, m.UserID))
+}
+
+func (m *GateMember) DisplayName() string {
+	if m.Username != "" {
+		return "@" + m.Username
+	}
+	if m.Name != "" {
+		return m.Name
+	}
+	return fmt.Sprintf("%d", m.UserID)
+}
+
+func FindGateMember(chatID, userID int64) (*GateMember, error) {
+	storeInstance := store.GetInstance()
+	data, err := storeInstance.Get(GetGateMemberKey(chatID, userID))
+	if err != nil {
+		return nil, err
+	}
+
+	var member GateMember
+	if err := msgpack.Unmarshal(data, &member); err != nil {
+		return nil, err
+	}
+
+	return &member, nil
+}
+
+func FindGateMembers(chatID int64) []*GateMember {
+	storeInstance := store.GetInstance()
+	iterator := storeInstance.Iterator()
+	defer iterator.Release()
+
+	prefix := []byte(fmt.Sprintf("gate_member:%d:", chatID))
+	members := make([]*GateMember, 0)
+
+	for iterator.Next() {
+		if !bytes.HasPrefix(iterator.Key(), prefix) {
+			continue
+		}
+
+		var member GateMember
+		if err := msgpack.Unmarshal(iterator.Value(), &member); err != nil {
+			continue
+		}
+
+		members = append(members, &member)
+	}
+
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].UserID < members[j].UserID
+	})
+
+	return members
+}
+
+func GetGateMemberKey(chatID, userID int64) []byte {
+	return []byte(fmt.Sprintf("gate_member:%d:%d", chatID, userID))
+}
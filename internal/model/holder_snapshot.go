@@ -0,0 +1,56 @@
+package model
+
+import (
+	"consul-telegram-bot/internal/store"
+	"fmt"
+	"time"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+const snapshotDateLayout = "2006-01-02"
+
+type HolderSnapshot struct {
+	Mint            string  `msgpack:"mint"`
+	Date            string  `msgpack:"date"`
+	Holders         int     `msgpack:"holders"`
+	HoldersComplete bool    `msgpack:"holders_complete"`
+	Supply          string  `msgpack:"supply"`
+	Decimals        int     `msgpack:"decimals"`
+	Top10Percent    float64 `msgpack:"top10_percent"`
+	CreatedAt       int64   `msgpack:"created_at"`
+}
+
+func (s *HolderSnapshot) Save() error {
+	key := GetHolderSnapshotKey(s.Mint, s.Date)
+	data, err := msgpack.Marshal(s)
+	if err != nil {
+		return err
+	}
+
+	storeInstance := store.GetInstance()
+	return storeInstance.Put(key, data)
+}
+
+func FindHolderSnapshot(mint string, date string) (*HolderSnapshot, error) {
+	storeInstance := store.GetInstance()
+	data, err := storeInstance.Get(GetHolderSnapshotKey(mint, date))
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshot HolderSnapshot
+	if err := msgpack.Unmarshal(data, &snapshot); err != nil {
+		return nil, err
+	}
+
+	return &snapshot, nil
+}
+
+func SnapshotDate(t time.Time) string {
+	return t.UTC().Format(snapshotDateLayout)
+}
+
+func GetHolderSnapshotKey(mint string, date string) []byte {
+	return []byte(fmt.Sprintf("holder_snapshot:%s:%s", mint, date))
+}
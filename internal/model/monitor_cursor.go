@@ -0,0 +1,49 @@
+package model
+
+import (
+	"consul-telegram-bot/internal/store"
+	"fmt"
+	"time"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+type MonitorCursor struct {
+	TokenAddress  string   `msgpack:"token_address"`
+	LastSignature string   `msgpack:"last_signature"`
+	ProcessedSigs []string `msgpack:"processed_sigs"`
+	UpdatedAt     int64    `msgpack:"updated_at"`
+}
+
+func (c *MonitorCursor) Save() error {
+	c.UpdatedAt = time.Now().Unix()
+
+	key := GetMonitorCursorKey(c.TokenAddress)
+	data, err := msgpack.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	storeInstance := store.GetInstance()
+	return storeInstance.Put(key, data)
+}
+
+func FindMonitorCursor(tokenAddress string) (*MonitorCursor, error) {
+	storeInstance := store.GetInstance()
+	key := GetMonitorCursorKey(tokenAddress)
+	data, err := storeInstance.Get(key)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor MonitorCursor
+	if err := msgpack.Unmarshal(data, &cursor); err != nil {
+		return nil, err
+	}
+
+	return &cursor, nil
+}
+
+func GetMonitorCursorKey(tokenAddress string) []byte {
+	return []byte(fmt.Sprintf("monitor_cursor:%s", tokenAddress))
+}
@@ -11,6 +11,13 @@ import (
 
 var mu sync.RWMutex
 
+const MaxGifs = 20
+
+var (
+	changeHandlersMu sync.RWMutex
+	changeHandlers   []func()
+)
+
 type RecipientType string
 
 const (
@@ -25,7 +32,10 @@ type Recipient struct {
 	Type               RecipientType
 	ThreadId           int
 	BuysThreadId       int
+	SellsThreadId      int
 	RetransmitThreadId int
+	SnipersThreadId    int
+	WalletsThreadId    int
 	Receiving          int64
 	ProjectName        string
 	TokenTicker        string
@@ -34,6 +44,19 @@ type Recipient struct {
 	TokenAddress       string
 	DexURL             string
 	AxiomURL           string
+	MinBuySol          float64
+	MinBuyUSD          float64
+	BuyTiers           []BuyTier
+	BuyDigestMinutes   int
+	MinSellSol         float64
+	GifFileIDs         []string
+	NewHoldersOnly     bool
+	Venues             []string
+	GateMinBalance     float64
+	GateAction         string
+	BuyTemplate        string
+	BuyButtons         []BuyButton
+	Explorer           string
 }
 
 func NewRecipient(id int64, recipientType RecipientType, threadId int) (*Recipient, error) {
@@ -67,6 +90,8 @@ func NewRecipient(id int64, recipientType RecipientType, threadId int) (*Recipie
 		return r, err
 	}
 
+	notifyRecipientsChanged()
+
 	return r, nil
 }
 
@@ -87,6 +112,8 @@ func (r *Recipient) Write() error {
 		return err
 	}
 
+	notifyRecipientsChanged()
+
 	return nil
 }
 
@@ -110,8 +137,14 @@ func (r *Recipient) DefineThreadIdForSignalType(signalType SignalType, threadId
 	switch signalType {
 	case SignalTypeBuys:
 		r.BuysThreadId = threadId
+	case SignalTypeSells:
+		r.SellsThreadId = threadId
 	case SignalTypeRetransmit:
 		r.RetransmitThreadId = threadId
+	case SignalTypeSnipers:
+		r.SnipersThreadId = threadId
+	case SignalTypeWallets:
+		r.WalletsThreadId = threadId
 	}
 }
 
@@ -119,13 +152,47 @@ func (r *Recipient) GetThreadIdForSignalType(signalType SignalType) int {
 	switch signalType {
 	case SignalTypeBuys:
 		return r.BuysThreadId
+	case SignalTypeSells:
+		return r.SellsThreadId
 	case SignalTypeRetransmit:
 		return r.RetransmitThreadId
+	case SignalTypeSnipers:
+		return r.SnipersThreadId
+	case SignalTypeWallets:
+		return r.WalletsThreadId
 	default:
 		return 0
 	}
 }
 
+func (r *Recipient) AddGif(fileID string) bool {
+	if len(r.GifFileIDs) >= MaxGifs {
+		return false
+	}
+
+	for _, id := range r.GifFileIDs {
+		if id == fileID {
+			return false
+		}
+	}
+
+	r.GifFileIDs = append(r.GifFileIDs, fileID)
+	return true
+}
+
+func (r *Recipient) RemoveGif(index int) bool {
+	if index < 0 || index >= len(r.GifFileIDs) {
+		return false
+	}
+
+	r.GifFileIDs = append(r.GifFileIDs[:index], r.GifFileIDs[index+1:]...)
+	return true
+}
+
+func (r *Recipient) ResetGifs() {
+	r.GifFileIDs = nil
+}
+
 func (r *Recipient) DeleteSelf() error {
 	storeInstance := store.GetInstance()
 	err := storeInstance.Delete(GetRecipientKey(r.Id))
@@ -133,6 +200,8 @@ func (r *Recipient) DeleteSelf() error {
 		return err
 	}
 
+	notifyRecipientsChanged()
+
 	return nil
 }
 
@@ -194,6 +263,23 @@ func IterateRecipients(fn func(*Recipient)) {
 	}
 }
 
+func OnRecipientsChanged(fn func()) {
+	changeHandlersMu.Lock()
+	defer changeHandlersMu.Unlock()
+	changeHandlers = append(changeHandlers, fn)
+}
+
+func notifyRecipientsChanged() {
+	changeHandlersMu.RLock()
+	handlers := make([]func(), len(changeHandlers))
+	copy(handlers, changeHandlers)
+	changeHandlersMu.RUnlock()
+
+	for _, fn := range handlers {
+		fn()
+	}
+}
+
 func GetRecipientKey(id int64) []byte {
 	return []byte(fmt.Sprintf("recipient:%d", id))
 }
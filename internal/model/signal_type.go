@@ -4,7 +4,10 @@ type SignalType string
 
 const (
 	SignalTypeBuys       SignalType = "buys"
+	SignalTypeSells      SignalType = "sells"
 	SignalTypeRetransmit SignalType = "retransmit"
+	SignalTypeSnipers    SignalType = "snipers"
+	SignalTypeWallets    SignalType = "wallets"
 )
 
 func (st SignalType) String() string {
@@ -15,8 +18,14 @@ func ParseSignalType(s string) (SignalType, bool) {
 	switch s {
 	case "buys":
 		return SignalTypeBuys, true
+	case "sells":
+		return SignalTypeSells, true
 	case "retransmit":
 		return SignalTypeRetransmit, true
+	case "snipers":
+		return SignalTypeSnipers, true
+	case "wallets":
+		return SignalTypeWallets, true
 	default:
 		return "", false
 	}
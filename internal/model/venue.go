@@ -0,0 +1,44 @@
+package model
+
+const (
+	VenueRaydium  = "raydium"
+	VenuePumpFun  = "pumpfun"
+	VenuePumpSwap = "pumpswap"
+	VenueMeteora  = "meteora"
+	VenueOrca     = "orca"
+	VenueJupiter  = "jupiter"
+)
+
+var Venues = []string{
+	VenueRaydium,
+	VenuePumpFun,
+	VenuePumpSwap,
+	VenueMeteora,
+	VenueOrca,
+	VenueJupiter,
+}
+
+func IsKnownVenue(venue string) bool {
+	for _, v := range Venues {
+		if v == venue {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Recipient) AcceptsVenue(venues ...string) bool {
+	if len(r.Venues) == 0 {
+		return true
+	}
+
+	for _, allowed := range r.Venues {
+		for _, venue := range venues {
+			if venue == allowed {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,176 @@
+package model
+
+import (
+	"bytes"
+	"consul-telegram-bot/internal/store"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+const WalletChallengeTTL = 10 * time.Minute
+
+var (
+	walletHandlersMu sync.RWMutex
+	walletHandlers   []func(userID int64)
+)
+
+type UserWallet struct {
+	Address    string `msgpack:"address"`
+	UserID     int64  `msgpack:"user_id"`
+	Username   string `msgpack:"username"`
+	VerifiedAt int64  `msgpack:"verified_at"`
+}
+
+type WalletChallenge struct {
+	UserID    int64  `msgpack:"user_id"`
+	Nonce     string `msgpack:"nonce"`
+	ExpiresAt int64  `msgpack:"expires_at"`
+}
+
+func (w *UserWallet) Save() error {
+	key := GetUserWalletKey(w.Address)
+	data, err := msgpack.Marshal(w)
+	if err != nil {
+		return err
+	}
+
+	storeInstance := store.GetInstance()
+	if err := storeInstance.Put(key, data); err != nil {
+		return err
+	}
+
+	notifyWalletsChanged(w.UserID)
+	return nil
+}
+
+func (w *UserWallet) Delete() error {
+	storeInstance := store.GetInstance()
+	if err := storeInstance.Delete(GetUserWalletKey(w.Address)); err != nil {
+		return err
+	}
+
+	notifyWalletsChanged(w.UserID)
+	return nil
+}
+
+func FindUserWallet(address string) (*UserWallet, error) {
+	storeInstance := store.GetInstance()
+	data, err := storeInstance.Get(GetUserWalletKey(address))
+	if err != nil {
+		return nil, err
+	}
+
+	var wallet UserWallet
+	if err := msgpack.Unmarshal(data, &wallet); err != nil {
+		return nil, err
+	}
+
+	return &wallet, nil
+}
+
+func FindAllUserWallets() []*UserWallet {
+	storeInstance := store.GetInstance()
+	iterator := storeInstance.Iterator()
+	defer iterator.Release()
+
+	prefix := []byte("wallet:")
+	wallets := make([]*UserWallet, 0)
+
+	for iterator.Next() {
+		if !bytes.HasPrefix(iterator.Key(), prefix) {
+			continue
+		}
+
+		var wallet UserWallet
+		if err := msgpack.Unmarshal(iterator.Value(), &wallet); err != nil {
+			continue
+		}
+
+		wallets = append(wallets, &wallet)
+	}
+
+	return wallets
+}
+
+func FindWalletsByUser(userID int64) []*UserWallet {
+	wallets := make([]*UserWallet, 0)
+	for _, wallet := range FindAllUserWallets() {
+		if wallet.UserID == userID {
+			wallets = append(wallets, wallet)
+		}
+	}
+
+	sort.Slice(wallets, func(i, j int) bool {
+		return wallets[i].VerifiedAt < wallets[j].VerifiedAt
+	})
+
+	return wallets
+}
+
+func (c *WalletChallenge) Save() error {
+	key := GetWalletChallengeKey(c.UserID)
+	data, err := msgpack.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	storeInstance := store.GetInstance()
+	return storeInstance.Put(key, data)
+}
+
+func (c *WalletChallenge) Delete() error {
+	storeInstance := store.GetInstance()
+	return storeInstance.Delete(GetWalletChallengeKey(c.UserID))
+}
+
+func (c *WalletChallenge) Expired() bool {
+	return time.Now().Unix() > c.ExpiresAt
+}
+
+func (c *WalletChallenge) Message() string {
+	return fmt.Sprintf("Consul wallet verification\nTelegram user: %d\nNonce: %s", c.UserID, c.Nonce)
+}
+
+func FindWalletChallenge(userID int64) (*WalletChallenge, error) {
+	storeInstance := store.GetInstance()
+	data, err := storeInstance.Get(GetWalletChallengeKey(userID))
+	if err != nil {
+		return nil, err
+	}
+
+	var challenge WalletChallenge
+	if err := msgpack.Unmarshal(data, &challenge); err != nil {
+		return nil, err
+	}
+
+	return &challenge, nil
+}
+
+func OnWalletsChanged(fn func(userID int64)) {
+	walletHandlersMu.Lock()
+	defer walletHandlersMu.Unlock()
+	walletHandlers = append(walletHandlers, fn)
+}
+
+func notifyWalletsChanged(userID int64) {
+	walletHandlersMu.RLock()
+	handlers := make([]func(int64), len(walletHandlers))
+	copy(handlers, walletHandlers)
+	walletHandlersMu.RUnlock()
+
+	for _, fn := range handlers {
+		fn(userID)
+	}
+}
+
+func GetUserWalletKey(address string) []byte {
+	return []byte(fmt.Sprintf("wallet:%s", address))
+}
+
+func GetWalletChallengeKey(userID int64) []byte {
+	return []byte(fmt.Sprintf("wallet_challenge:%d", userID))
+}
@@ -0,0 +1,88 @@
+package model
+
+import (
+	"bytes"
+	"consul-telegram-bot/internal/store"
+	"fmt"
+	"sort"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+type WalletLabel struct {
+	ChatID  int64  `msgpack:"chat_id"`
+	Address string `msgpack:"address"`
+	Label   string `msgpack:"label"`
+	Ignored bool   `msgpack:"ignored"`
+}
+
+func (l *WalletLabel) Save() error {
+	key := GetWalletLabelKey(l.ChatID, l.Address)
+	data, err := msgpack.Marshal(l)
+	if err != nil {
+		return err
+	}
+
+	storeInstance := store.GetInstance()
+	return storeInstance.Put(key, data)
+}
+
+func (l *WalletLabel) Delete() error {
+	storeInstance := store.GetInstance()
+	return storeInstance.Delete(GetWalletLabelKey(l.ChatID, l.Address))
+}
+
+func FindWalletLabel(chatID int64, address string) (*WalletLabel, error) {
+	storeInstance := store.GetInstance()
+	data, err := storeInstance.Get(GetWalletLabelKey(chatID, address))
+	if err != nil {
+		return nil, err
+	}
+
+	var label WalletLabel
+	if err := msgpack.Unmarshal(data, &label); err != nil {
+		return nil, err
+	}
+
+	return &label, nil
+}
+
+func FindWalletLabels(chatID int64) []*WalletLabel {
+	storeInstance := store.GetInstance()
+	iterator := storeInstance.Iterator()
+	defer iterator.Release()
+
+	prefix := []byte(fmt.Sprintf("wallet_label:%d:", chatID))
+	labels := make([]*WalletLabel, 0)
+
+	for iterator.Next() {
+		if !bytes.HasPrefix(iterator.Key(), prefix) {
+			continue
+		}
+
+		var label WalletLabel
+		if err := msgpack.Unmarshal(iterator.Value(), &label); err != nil {
+			continue
+		}
+
+		labels = append(labels, &label)
+	}
+
+	sort.Slice(labels, func(i, j int) bool {
+		if labels[i].Ignored != labels[j].Ignored {
+			return !labels[i].Ignored
+		}
+		return labels[i].Label < labels[j].Label
+	})
+
+	return labels
+}
+
+func IsWalletIgnored(chatID int64, address string) bool {
+	label, err := FindWalletLabel(chatID, address)
+	return err == nil && label.Ignored
+}
+
+func GetWalletLabelKey(chatID int64, address string) []byte {
+	return []byte(fmt.Sprintf("wallet_label:%d:%s", chatID, address))
+}
@@ -0,0 +1,161 @@
+package model
+
+import (
+	"bytes"
+	"consul-telegram-bot/internal/store"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+const MaxWatchedWallets = 20
+
+type WatchedWallet struct {
+	ChatID    int64  `msgpack:"chat_id"`
+	Address   string `msgpack:"address"`
+	Label     string `msgpack:"label"`
+	CreatedAt int64  `msgpack:"created_at"`
+}
+
+type WalletWatchCursor struct {
+	Address       string `msgpack:"address"`
+	LastSignature string `msgpack:"last_signature"`
+	UpdatedAt     int64  `msgpack:"updated_at"`
+}
+
+func (w *WatchedWallet) Save() error {
+	if w.CreatedAt == 0 {
+		w.CreatedAt = time.Now().Unix()
+	}
+
+	key := GetWatchedWalletKey(w.ChatID, w.Address)
+	data, err := msgpack.Marshal(w)
+	if err != nil {
+		return err
+	}
+
+	storeInstance := store.GetInstance()
+	return storeInstance.Put(key, data)
+}
+
+func (w *WatchedWallet) Delete() error {
+	storeInstance := store.GetInstance()
+	return storeInstance.Delete(GetWatchedWalletKey(w.ChatID, w.Address))
+}
+
+func FindWatchedWallet(chatID int64, address string) (*WatchedWallet, error) {
+	storeInstance := store.GetInstance()
+	data, err := storeInstance.Get(GetWatchedWalletKey(chatID, address))
+	if err != nil {
+		return nil, err
+	}
+
+	var wallet WatchedWallet
+	if err := msgpack.Unmarshal(data, &wallet); err != nil {
+		return nil, err
+	}
+
+	return &wallet, nil
+}
+
+func FindWatchedWallets(chatID int64) []*WatchedWallet {
+	return findWatchedWallets([]byte(fmt.Sprintf("watched_wallet:%d:", chatID)))
+}
+
+func FindAllWatchedWallets() []*WatchedWallet {
+	return findWatchedWallets([]byte("watched_wallet:"))
+}
+
+func findWatchedWallets(prefix []byte) []*WatchedWallet {
+	storeInstance := store.GetInstance()
+	iterator := storeInstance.Iterator()
+	defer iterator.Release()
+
+	wallets := make([]*WatchedWallet, 0)
+
+	for iterator.Next() {
+		if !bytes.HasPrefix(iterator.Key(), prefix) {
+			continue
+		}
+
+		var wallet WatchedWallet
+		if err := msgpack.Unmarshal(iterator.Value(), &wallet); err != nil {
+			continue
+		}
+
+		wallets = append(wallets, &wallet)
+	}
+
+	sort.Slice(wallets, func(i, j int) bool {
+		return wallets[i].Label < wallets[j].Label
+	})
+
+	return wallets
+}
+
+func (c *WalletWatchCursor) Save() error {
+	c.UpdatedAt = time.Now().Unix()
+
+	key := GetWalletWatchCursorKey(c.Address)
+	data, err := msgpack.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	storeInstance := store.GetInstance()
+	return storeInstance.Put(key, data)
+}
+
+func (c *WalletWatchCursor) Delete() error {
+	storeInstance := store.GetInstance()
+	return storeInstance.Delete(GetWalletWatchCursorKey(c.Address))
+}
+
+func FindAllWalletWatchCursors() []*WalletWatchCursor {
+	storeInstance := store.GetInstance()
+	iterator := storeInstance.Iterator()
+	defer iterator.Release()
+
+	prefix := []byte("wallet_watch_cursor:")
+	cursors := make([]*WalletWatchCursor, 0)
+
+	for iterator.Next() {
+		if !bytes.HasPrefix(iterator.Key(), prefix) {
+			continue
+		}
+
+		var cursor WalletWatchCursor
+		if err := msgpack.Unmarshal(iterator.Value(), &cursor); err != nil {
+			continue
+		}
+
+		cursors = append(cursors, &cursor)
+	}
+
+	return cursors
+}
+
+func FindWalletWatchCursor(address string) (*WalletWatchCursor, error) {
+	storeInstance := store.GetInstance()
+	data, err := storeInstance.Get(GetWalletWatchCursorKey(address))
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor WalletWatchCursor
+	if err := msgpack.Unmarshal(data, &cursor); err != nil {
+		return nil, err
+	}
+
+	return &cursor, nil
+}
+
+func GetWatchedWalletKey(chatID int64, address string) []byte {
+	return []byte(fmt.Sprintf("watched_wallet:%d:%s", chatID, address))
+}
+
+func GetWalletWatchCursorKey(address string) []byte {
+	return []byte(fmt.Sprintf("wallet_watch_cursor:%s", address))
+}
@@ -2,6 +2,7 @@ package router
 
 import (
 	"consul-telegram-bot/internal/bot"
+	"consul-telegram-bot/internal/buybot"
 	"consul-telegram-bot/internal/config"
 	"consul-telegram-bot/internal/logger"
 	"consul-telegram-bot/internal/model"
@@ -17,6 +18,7 @@ type Context struct {
 	Bot     *bot.Bot
 	Logger  *logger.Logger
 	Config  *config.Config
+	Helius  *buybot.HeliusClient
 }
 
 func (c Context) GetArgString() string {
@@ -2,6 +2,7 @@ package router
 
 import (
 	"consul-telegram-bot/internal/bot"
+	"consul-telegram-bot/internal/buybot"
 	"consul-telegram-bot/internal/config"
 	"consul-telegram-bot/internal/logger"
 	"consul-telegram-bot/internal/metrics"
@@ -24,6 +25,7 @@ type Router struct {
 	config   *config.Config
 	logger   *logger.Logger
 	buttons  map[string]string
+	helius   *buybot.HeliusClient
 }
 
 func New(b *bot.Bot, l *logger.Logger, c *config.Config) *Router {
@@ -36,6 +38,14 @@ func New(b *bot.Bot, l *logger.Logger, c *config.Config) *Router {
 	}
 }
 
+// SetHeliusClient shares the buy bot's RPC client with commands that read
+// on-chain data.
+func (r *Router) SetHeliusClient(client *buybot.HeliusClient) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.helius = client
+}
+
 func (r *Router) AddCommand(command string, callback Callback) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -158,6 +168,7 @@ func (r *Router) parseMessage(m *telebot.Message) *Context {
 		Bot:     r.bot,
 		Logger:  r.logger,
 		Config:  r.config,
+		Helius:  r.helius,
 		Message: m,
 	}
 }
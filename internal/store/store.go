@@ -9,6 +9,7 @@ import (
 	"github.com/syndtr/goleveldb/leveldb/filter"
 	"github.com/syndtr/goleveldb/leveldb/iterator"
 	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
 )
 
 type Store struct {
@@ -126,6 +127,16 @@ func (s *Store) Iterator() iterator.Iterator {
 	return s.db.NewIterator(nil, nil)
 }
 
+// PrefixIterator iterates over the keys with prefix in order, starting at
+// start when it is set, without scanning the rest of the database.
+func (s *Store) PrefixIterator(prefix []byte, start []byte) iterator.Iterator {
+	keyRange := util.BytesPrefix(prefix)
+	if start != nil {
+		keyRange.Start = start
+	}
+	return s.db.NewIterator(keyRange, nil)
+}
+
 func (s *Store) MakeGlobal() {
 	globalInstance = s
 }
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"html"
 	"math"
+	"math/big"
+	"strconv"
 	"strings"
 )
 
@@ -17,34 +19,18 @@ func FormatPercentage(value float64, decimals int) string {
 }
 
 func FormatNumber(value float64, sign string) string {
-	const (
-		TRILLION = 1_000_000_000_000
-		BILLION  = 1_000_000_000
-		MILLION  = 1_000_000
-		THOUSAND = 1_000
-	)
+	suffixes := []string{"", "K", "M", "B", "T"}
 
-	absValue := math.Abs(value)
+	scaled := value
+	unit := 0
+	for unit < len(suffixes)-1 && math.Abs(math.Round(scaled*100)/100) >= 1000 {
+		scaled /= 1000
+		unit++
+	}
 
-	var formatted string
-	if absValue >= TRILLION {
-		formatted = fmt.Sprintf("%.2f", value/TRILLION)
-		formatted = strings.TrimRight(strings.TrimRight(formatted, "0"), ".")
-		formatted += "T"
-	} else if absValue >= BILLION {
-		formatted = fmt.Sprintf("%.2f", value/BILLION)
-		formatted = strings.TrimRight(strings.TrimRight(formatted, "0"), ".")
-		formatted += "B"
-	} else if absValue >= MILLION {
-		formatted = fmt.Sprintf("%.2f", value/MILLION)
-		formatted = strings.TrimRight(strings.TrimRight(formatted, "0"), ".")
-		formatted += "M"
-	} else if absValue >= THOUSAND {
-		formatted = fmt.Sprintf("%.2f", value/THOUSAND)
-		formatted = strings.TrimRight(strings.TrimRight(formatted, "0"), ".")
-		formatted += "K"
-	} else {
-		formatted = fmt.Sprintf("%.2f", value)
+	formatted := fmt.Sprintf("%.2f", scaled)
+	if unit > 0 {
+		formatted = strings.TrimRight(strings.TrimRight(formatted, "0"), ".") + suffixes[unit]
 	}
 
 	if sign != "" {
@@ -54,10 +40,61 @@ func FormatNumber(value float64, sign string) string {
 	return formatted
 }
 
+func FormatCount(value int) string {
+	if value < 0 {
+		return "-" + FormatCount(-value)
+	}
+
+	digits := strconv.Itoa(value)
+
+	var sb strings.Builder
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteRune(digit)
+	}
+	return sb.String()
+}
+
+func TokenAmountToFloat(amount *big.Int, decimals int) float64 {
+	if amount == nil {
+		return 0
+	}
+
+	denominator := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	value, _ := new(big.Rat).SetFrac(amount, denominator).Float64()
+	return value
+}
+
+func FormatTokenAmount(amount *big.Int, decimals int, sign string) string {
+	return FormatNumber(TokenAmountToFloat(amount, decimals), sign)
+}
+
+func ShortenAddress(address string) string {
+	if len(address) <= 12 {
+		return address
+	}
+	return address[:6] + "..." + address[len(address)-4:]
+}
+
 func FormatUSD(value float64) string {
 	return "$" + FormatNumber(value, "")
 }
 
+func FormatPrice(value float64) string {
+	absValue := math.Abs(value)
+	if absValue == 0 || absValue >= 1 {
+		return "$" + strconv.FormatFloat(value, 'f', 2, 64)
+	}
+
+	decimals := int(math.Ceil(-math.Log10(absValue))) + 3
+	formatted := strconv.FormatFloat(value, 'f', decimals, 64)
+	formatted = strings.TrimRight(strings.TrimRight(formatted, "0"), ".")
+
+	return "$" + formatted
+}
+
 func EscapeHTML(text string) string {
 	text = html.EscapeString(text)
 	return text
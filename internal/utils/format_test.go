@@ -0,0 +1,114 @@
+package utils
+
+import (
+	"math/big"
+	"testing"
+)
+
+func bigInt(t *testing.T, value string) *big.Int {
+	t.Helper()
+
+	amount, ok := new(big.Int).SetString(value, 10)
+	if !ok {
+		t.Fatalf("invalid big.Int: %s", value)
+	}
+	return amount
+}
+
+func TestTokenAmountToFloat(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   string
+		decimals int
+		want     float64
+	}{
+		{name: "zero decimals", amount: "42", decimals: 0, want: 42},
+		{name: "six decimals", amount: "1500000", decimals: 6, want: 1.5},
+		{name: "just above 2^64 raw", amount: "18446744073709551617", decimals: 0, want: 18446744073709551616},
+		{name: "huge supply with nine decimals", amount: "1000000000000000000000000000", decimals: 9, want: 1e18},
+		{name: "huge supply with eighteen decimals", amount: "123456789000000000000000000000", decimals: 18, want: 123456789000},
+		{name: "fraction of a unit", amount: "1", decimals: 9, want: 1e-9},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TokenAmountToFloat(bigInt(t, tt.amount), tt.decimals); got != tt.want {
+				t.Errorf("TokenAmountToFloat(%s, %d) = %v, want %v", tt.amount, tt.decimals, got, tt.want)
+			}
+		})
+	}
+
+	if got := TokenAmountToFloat(nil, 6); got != 0 {
+		t.Errorf("TokenAmountToFloat(nil) = %v, want 0", got)
+	}
+}
+
+func TestFormatTokenAmount(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   string
+		decimals int
+		want     string
+	}{
+		{name: "zero decimals", amount: "1234", decimals: 0, want: "1.23K TOKEN"},
+		{name: "zero decimals below a thousand", amount: "999", decimals: 0, want: "999.00 TOKEN"},
+		{name: "rounds up into the next unit", amount: "999999", decimals: 0, want: "1M TOKEN"},
+		{name: "rounds up below a thousand", amount: "999999", decimals: 3, want: "1K TOKEN"},
+		{name: "trims trailing zeros", amount: "1005000000", decimals: 6, want: "1K TOKEN"},
+		{name: "pump.fun supply", amount: "1000000000000000", decimals: 6, want: "1B TOKEN"},
+		{name: "just above 2^64 raw", amount: "18446744073709551617", decimals: 0, want: "18446744.07T TOKEN"},
+		{name: "huge supply with nine decimals", amount: "1000000000000000000000000000", decimals: 9, want: "1000000T TOKEN"},
+		{name: "zero", amount: "0", decimals: 6, want: "0.00 TOKEN"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatTokenAmount(bigInt(t, tt.amount), tt.decimals, "TOKEN"); got != tt.want {
+				t.Errorf("FormatTokenAmount(%s, %d) = %q, want %q", tt.amount, tt.decimals, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShortenAddress(t *testing.T) {
+	tests := map[string]string{
+		"7xKXtg2CW87d97TXJSDpbD5jBkheTqA83TZRuJosgAsU": "7xKXtg...gAsU",
+		"short":        "short",
+		"exactly12chr": "exactly12chr",
+	}
+
+	for address, want := range tests {
+		if got := ShortenAddress(address); got != want {
+			t.Errorf("ShortenAddress(%q) = %q, want %q", address, got, want)
+		}
+	}
+}
+
+func TestFormatChange(t *testing.T) {
+	tests := map[float64]string{
+		12.345: "12.35%",
+		-2.5:   "-2.50%",
+		0:      "0.00%",
+	}
+
+	for change, want := range tests {
+		if got := FormatChange(change); got != want {
+			t.Errorf("FormatChange(%v) = %q, want %q", change, got, want)
+		}
+	}
+}
+
+func TestFormatTimeframe(t *testing.T) {
+	tests := map[string]string{
+		"1h":  "1 hour",
+		"6h":  "6 hours",
+		"24h": "24 hours",
+		"5m":  "5m",
+	}
+
+	for frame, want := range tests {
+		if got := FormatTimeframe(frame); got != want {
+			t.Errorf("FormatTimeframe(%q) = %q, want %q", frame, got, want)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/mr-tron/base58"
+)
+
+func VerifySolanaSignature(address string, message string, signature string) error {
+	publicKey, err := base58.Decode(address)
+	if err != nil || len(publicKey) != ed25519.PublicKeySize {
+		return fmt.Errorf("invalid address: %s", address)
+	}
+
+	sig, err := base58.Decode(signature)
+	if err != nil || len(sig) != ed25519.SignatureSize {
+		sig, err = base64.StdEncoding.DecodeString(signature)
+		if err != nil || len(sig) != ed25519.SignatureSize {
+			return fmt.Errorf("invalid signature encoding")
+		}
+	}
+
+	if !ed25519.Verify(ed25519.PublicKey(publicKey), []byte(message), sig) {
+		return fmt.Errorf("signature does not match")
+	}
+
+	return nil
+}
+
+func IsSolanaAddress(address string) bool {
+	publicKey, err := base58.Decode(address)
+	return err == nil && len(publicKey) == ed25519.PublicKeySize
+}
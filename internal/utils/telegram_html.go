@@ -0,0 +1,99 @@
+package utils
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"unicode/utf16"
+)
+
+var telegramHTMLTags = map[string]bool{
+	"b":          true,
+	"strong":     true,
+	"i":          true,
+	"em":         true,
+	"u":          true,
+	"ins":        true,
+	"s":          true,
+	"strike":     true,
+	"del":        true,
+	"span":       true,
+	"tg-spoiler": true,
+	"tg-emoji":   true,
+	"a":          true,
+	"code":       true,
+	"pre":        true,
+	"blockquote": true,
+}
+
+var (
+	telegramHTMLEntity = regexp.MustCompile(`^&(lt|gt|amp|quot|#[0-9]+|#x[0-9a-fA-F]+);`)
+	htmlTagPattern     = regexp.MustCompile(`<[^>]*>`)
+)
+
+func ValidateTelegramHTML(text string) error {
+	var open []string
+
+	for i := 0; i < len(text); {
+		switch text[i] {
+		case '<':
+			end := strings.IndexByte(text[i:], '>')
+			if end == -1 {
+				return fmt.Errorf("unclosed \"<\" at position %d", i)
+			}
+
+			tag := text[i+1 : i+end]
+			closing := strings.HasPrefix(tag, "/")
+			fields := strings.Fields(strings.TrimPrefix(tag, "/"))
+			if len(fields) == 0 {
+				return fmt.Errorf("empty tag at position %d", i)
+			}
+
+			name := strings.ToLower(fields[0])
+			if !telegramHTMLTags[name] {
+				return fmt.Errorf("unsupported tag <%s>", name)
+			}
+
+			if closing {
+				if len(open) == 0 || open[len(open)-1] != name {
+					return fmt.Errorf("unexpected closing tag </%s>", name)
+				}
+				open = open[:len(open)-1]
+			} else {
+				if name == "a" && !strings.Contains(strings.ToLower(tag), "href=") {
+					return fmt.Errorf("<a> tag without href")
+				}
+				if name == "span" && strings.Join(fields[1:], " ") != `class="tg-spoiler"` {
+					return fmt.Errorf("<span> is only supported as <span class=\"tg-spoiler\">")
+				}
+				open = append(open, name)
+			}
+
+			i += end + 1
+		case '&':
+			if !telegramHTMLEntity.MatchString(text[i:]) {
+				return fmt.Errorf("unescaped \"&\" at position %d, use &amp;amp;", i)
+			}
+			i++
+		default:
+			i++
+		}
+	}
+
+	if len(open) > 0 {
+		return fmt.Errorf("unclosed tag <%s>", open[len(open)-1])
+	}
+
+	return nil
+}
+
+// TelegramHTMLLength returns the length Telegram checks its limits against:
+// UTF-16 code units of the text with tags stripped and entities decoded.
+func TelegramHTMLLength(text string) int {
+	length := 0
+	for _, r := range html.UnescapeString(htmlTagPattern.ReplaceAllString(text, "")) {
+		length += utf16.RuneLen(r)
+	}
+	return length
+}
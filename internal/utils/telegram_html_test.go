@@ -0,0 +1,59 @@
+package utils
+
+import "testing"
+
+func TestTelegramHTMLLength(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want int
+	}{
+		{name: "plain text", text: "Buy!", want: 4},
+		{name: "tags are stripped", text: "<b>Buy</b> <a href=\"https://example.com\">tx</a>", want: 6},
+		{name: "entities count once", text: "&lt;b&gt; &amp;", want: 5},
+		{name: "cyrillic is one unit per letter", text: "Покупка", want: 7},
+		{name: "emoji outside the BMP take two units", text: "🟢🚀", want: 4},
+		{name: "emoji inside the BMP take one unit", text: "⚡", want: 1},
+		{name: "emoji with variation selector", text: "❤️", want: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TelegramHTMLLength(tt.text); got != tt.want {
+				t.Errorf("TelegramHTMLLength(%q) = %d, want %d", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateTelegramHTML(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		wantErr bool
+	}{
+		{name: "plain text", text: "Buy!"},
+		{name: "nested tags", text: "<b>Buy <i>now</i></b>"},
+		{name: "link", text: "<a href=\"https://example.com\">tx</a>"},
+		{name: "spoiler span", text: "<span class=\"tg-spoiler\">secret</span>"},
+		{name: "spoiler tag", text: "<tg-spoiler>secret</tg-spoiler>"},
+		{name: "escaped entities", text: "&lt;b&gt; &amp; &#128640;"},
+		{name: "span without class", text: "<span>text</span>", wantErr: true},
+		{name: "span with another class", text: "<span class=\"red\">text</span>", wantErr: true},
+		{name: "span with style", text: "<span style=\"color:red\">text</span>", wantErr: true},
+		{name: "unsupported tag", text: "<div>text</div>", wantErr: true},
+		{name: "link without href", text: "<a>tx</a>", wantErr: true},
+		{name: "unclosed tag", text: "<b>Buy", wantErr: true},
+		{name: "mismatched closing tag", text: "<b>Buy</i>", wantErr: true},
+		{name: "unescaped ampersand", text: "Buy & hold", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTelegramHTML(tt.text)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateTelegramHTML(%q) error = %v, wantErr %v", tt.text, err, tt.wantErr)
+			}
+		})
+	}
+}